@@ -0,0 +1,186 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package archive identifies archive formats by their signature.
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hkmh223/pd2mm/common/xz"
+)
+
+type Format int
+
+const (
+	Unknown Format = iota
+	Zip
+	SevenZip
+	Rar
+	Tar
+	TarGzip
+	TarXz
+	Gzip
+	Xz
+	TarBzip2
+	Bzip2
+	Cab
+)
+
+const (
+	tarMagicOffset = 257
+	tarBlockSize   = 512
+)
+
+type signature struct {
+	format Format
+	magic  []byte
+}
+
+// signatures returns the magic bytes at the start of each format.
+func signatures() []signature {
+	return []signature{
+		{format: Zip, magic: []byte("PK\x03\x04")},
+		{format: Zip, magic: []byte("PK\x05\x06")},
+		{format: Zip, magic: []byte("PK\x07\x08")},
+		{format: SevenZip, magic: []byte("7z\xBC\xAF\x27\x1C")},
+		{format: Rar, magic: []byte("Rar!\x1A\x07\x00")},
+		{format: Rar, magic: []byte("Rar!\x1A\x07\x01\x00")},
+		{format: Gzip, magic: []byte{0x1F, 0x8B}},
+		{format: Xz, magic: []byte{0xFD, '7', 'z', 'X', 'Z', 0x00}},
+		{format: Bzip2, magic: []byte("BZh")},
+		{format: Cab, magic: []byte("MSCF\x00\x00\x00\x00")},
+	}
+}
+
+// String returns the name of the format.
+func (f Format) String() string {
+	switch f {
+	case Zip:
+		return "zip"
+	case SevenZip:
+		return "7z"
+	case Rar:
+		return "rar"
+	case Tar:
+		return "tar"
+	case TarGzip:
+		return "tar.gz"
+	case TarXz:
+		return "tar.xz"
+	case Gzip:
+		return "gzip"
+	case Xz:
+		return "xz"
+	case TarBzip2:
+		return "tar.bz2"
+	case Bzip2:
+		return "bzip2"
+	case Cab:
+		return "cab"
+	case Unknown:
+	}
+
+	return "unknown"
+}
+
+// IsArchive returns true if the format is known.
+func (f Format) IsArchive() bool {
+	return f != Unknown
+}
+
+// IsPartial returns true if name is a download that has not finished yet.
+func IsPartial(name string) bool {
+	return slices.Contains([]string{".part", ".crdownload", ".download", ".partial", ".tmp"}, strings.ToLower(filepath.Ext(name)))
+}
+
+// DetectFile returns the format of the file at name.
+func DetectFile(name string) (Format, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return Unknown, err
+	}
+	defer file.Close()
+
+	return Detect(file)
+}
+
+// Detect returns the format of r from its signature. gzip, xz and bzip2 streams are decompressed
+// far enough to tell whether they contain a tar archive.
+func Detect(r io.Reader) (Format, error) {
+	reader := bufio.NewReaderSize(r, tarBlockSize)
+
+	header, err := reader.Peek(tarBlockSize)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return Unknown, err
+	}
+
+	for _, signature := range signatures() {
+		if !bytes.HasPrefix(header, signature.magic) {
+			continue
+		}
+
+		switch signature.format {
+		case Gzip:
+			if decompressed, err := gzip.NewReader(reader); err == nil && isTar(decompressed) {
+				return TarGzip, nil
+			}
+		case Xz:
+			if decompressed, err := xz.NewReader(reader); err == nil && isTar(decompressed) {
+				return TarXz, nil
+			}
+		case Bzip2:
+			if isTar(bzip2.NewReader(reader)) {
+				return TarBzip2, nil
+			}
+		}
+
+		return signature.format, nil
+	}
+
+	if isTarHeader(header) {
+		return Tar, nil
+	}
+
+	return Unknown, nil
+}
+
+// isTar returns true if r starts with a tar header.
+func isTar(r io.Reader) bool {
+	header := make([]byte, tarBlockSize)
+
+	n, _ := io.ReadFull(r, header)
+
+	return isTarHeader(header[:n])
+}
+
+// isTarHeader returns true if header has the ustar magic of POSIX and GNU tar.
+func isTarHeader(header []byte) bool {
+	const magicLength = 5
+
+	return len(header) >= tarMagicOffset+magicLength && string(header[tarMagicOffset:tarMagicOffset+magicLength]) == "ustar"
+}
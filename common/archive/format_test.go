@@ -0,0 +1,129 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package archive_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"testing"
+
+	"github.com/hkmh223/pd2mm/common/archive"
+)
+
+// tarBzip2 is a bzip2 compressed tar archive containing mod.txt, since compress/bzip2 can only decompress.
+const tarBzip2 = "QlpoOTFBWSZTWe8UmaIAAG5bgMmAQAFXgAAgZAKeSggIIABUQgT0jQwm1PJoJJEGmj1AMhpakiCEH1wIRo1HkPnlggQwMdPEUWEcQEH+36v5Qic" +
+	"FjoZoqovLN2IiAbF3JFOFCQ7xSZog"
+
+// bzip2Text is "readme" compressed with bzip2.
+const bzip2Text = "BZh91AY&SY\xfb>\xf4@\x00\x00\x02\x81\x80&\x02\x10\x00 \x000\xcd\x00\xc1\xa5\x80\x1c]\xc9\x14\xe1BC\xec\xfb\xd1\x00"
+
+func tarBytes(t *testing.T) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	writer := tar.NewWriter(&buf)
+	if err := writer.WriteHeader(&tar.Header{Name: "mod.txt", Mode: 0o644, Size: 2, Format: tar.FormatPAX}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := writer.Write([]byte("{}")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+
+	var zipped bytes.Buffer
+
+	zipWriter := zip.NewWriter(&zipped)
+	if _, err := zipWriter.Create("mod.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := zipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var gzipped, gzippedText bytes.Buffer
+
+	for buf, data := range map[*bytes.Buffer][]byte{&gzipped: tarBytes(t), &gzippedText: []byte("readme")} {
+		writer := gzip.NewWriter(buf)
+		if _, err := writer.Write(data); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tarBzipped, err := base64.StdEncoding.DecodeString(tarBzip2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	formats := map[string]struct {
+		data   []byte
+		format archive.Format
+	}{
+		"zip":     {data: zipped.Bytes(), format: archive.Zip},
+		"7z":      {data: []byte("7z\xBC\xAF\x27\x1C\x00\x04"), format: archive.SevenZip},
+		"rar":     {data: []byte("Rar!\x1A\x07\x01\x00"), format: archive.Rar},
+		"tar":     {data: tarBytes(t), format: archive.Tar},
+		"tar.gz":  {data: gzipped.Bytes(), format: archive.TarGzip},
+		"gzip":    {data: gzippedText.Bytes(), format: archive.Gzip},
+		"xz":      {data: []byte{0xFD, '7', 'z', 'X', 'Z', 0x00, 0x00}, format: archive.Xz},
+		"tar.bz2": {data: tarBzipped, format: archive.TarBzip2},
+		"bzip2":   {data: []byte(bzip2Text), format: archive.Bzip2},
+		"cab":     {data: []byte("MSCF\x00\x00\x00\x00\x2C\x00\x00\x00"), format: archive.Cab},
+		"readme":  {data: []byte("Install by copying the folder."), format: archive.Unknown},
+		"empty":   {data: []byte{}, format: archive.Unknown},
+		"picture": {data: []byte("\x89PNG\r\n\x1A\n"), format: archive.Unknown},
+	}
+
+	for name, expected := range formats {
+		format, err := archive.Detect(bytes.NewReader(expected.data))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		if format != expected.format {
+			t.Errorf("%s: detected %s, want %s", name, format, expected.format)
+		}
+	}
+}
+
+func TestIsPartial(t *testing.T) {
+	t.Parallel()
+
+	if !archive.IsPartial("mod.zip.part") || !archive.IsPartial("mod.7z.crdownload") || archive.IsPartial("mod.zip") {
+		t.Fatal("unexpected partial download detection")
+	}
+}
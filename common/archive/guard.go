@@ -0,0 +1,223 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package archive
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hkmh223/pd2mm/common/filesystem"
+)
+
+var (
+	ErrAbsolutePath     = errors.New("entry has an absolute path")
+	ErrPathTraversal    = errors.New("entry escapes the destination directory")
+	ErrTooManyEntries   = errors.New("archive has too many entries")
+	ErrTooLarge         = errors.New("archive is larger than the maximum uncompressed size")
+	ErrCompressionRatio = errors.New("archive exceeds the maximum compression ratio")
+	ErrLink             = errors.New("links are not extracted")
+)
+
+// ratioFloor is the smallest archive size the compression ratio is measured against,
+// so small archives of text that compresses well are not mistaken for bombs.
+const ratioFloor = 1 << 20
+
+// Limits bounds what a single archive may extract, a zero value disables the limit.
+type Limits struct {
+	// MaxSize is the maximum total uncompressed size in bytes.
+	MaxSize uint64
+	// MaxRatio is the maximum ratio between the total uncompressed size and the size of the archive.
+	MaxRatio uint64
+	// MaxEntries is the maximum number of files and directories.
+	MaxEntries int
+}
+
+// Violation is an entry of an archive that breaks a rule of its Guard.
+type Violation struct {
+	Entry string
+	Err   error
+}
+
+func (v *Violation) Error() string {
+	return fmt.Sprintf("%s: %q", v.Err, v.Entry)
+}
+
+func (v *Violation) Unwrap() error {
+	return v.Err
+}
+
+// Guard checks the entries of one archive while it is extracted into a destination directory. Every backend asks it
+// where an entry may be written and writes entries through it, so sizes are enforced on the data actually written
+// rather than on the sizes the archive claims.
+type Guard struct {
+	dest     string
+	limits   Limits
+	names    string
+	cases    *filesystem.CaseCollisions
+	packed   uint64
+	entries  int
+	claimed  uint64
+	total    uint64
+	warnings []error
+}
+
+// NewGuard creates a Guard for the archive at src extracted into dest.
+func NewGuard(src, dest string, limits Limits) (*Guard, error) {
+	info, err := os.Stat(src)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Guard{
+		dest:     filepath.Clean(dest),
+		limits:   limits,
+		names:    filesystem.NamePolicyKeep,
+		cases:    filesystem.NewCaseCollisions(),
+		packed:   uint64(info.Size()), //nolint:gosec // reason: file sizes are not negative.
+		entries:  0,
+		claimed:  0,
+		total:    0,
+		warnings: nil,
+	}, nil
+}
+
+// SetNamePolicy sets what happens to entries with names that can not be created on Windows, see filesystem.CheckName.
+// Names are kept by default, every such name and every case collision is a warning.
+func (g *Guard) SetNamePolicy(policy string) {
+	g.names = policy
+}
+
+// Join returns where the archive path name ends up below dest. Names using '/' or '\' as separator are accepted,
+// absolute paths and paths leaving dest are violations.
+func Join(dest, name string) (string, error) {
+	slashed := strings.ReplaceAll(name, `\`, "/")
+
+	if strings.HasPrefix(slashed, "/") || filepath.VolumeName(slashed) != "" || (len(slashed) > 1 && slashed[1] == ':') {
+		return "", &Violation{Entry: name, Err: ErrAbsolutePath}
+	}
+
+	path := filepath.Join(dest, filepath.FromSlash(slashed))
+
+	rel, err := filepath.Rel(dest, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", &Violation{Entry: name, Err: ErrPathTraversal}
+	}
+
+	return path, nil
+}
+
+// Path returns where the entry name is extracted, names are archive paths using '/' or '\' as separator.
+// Absolute paths and paths leaving the destination are violations, names invalid on Windows follow the name policy.
+func (g *Guard) Path(name string) (string, error) {
+	path, err := Join(g.dest, name)
+	if err != nil {
+		return "", err
+	}
+
+	rel, err := filepath.Rel(g.dest, path)
+	if err != nil {
+		return "", &Violation{Entry: name, Err: ErrPathTraversal}
+	}
+
+	rel = filepath.ToSlash(rel)
+
+	if sanitized, problem := filesystem.SanitizePath(rel); problem != nil {
+		switch g.names {
+		case filesystem.NamePolicyReject:
+			return "", &Violation{Entry: name, Err: problem}
+		case filesystem.NamePolicyRename:
+			g.warnings = append(g.warnings, &Violation{Entry: name, Err: fmt.Errorf("%w, renamed to %q", problem, sanitized)})
+			rel = sanitized
+		default:
+			g.warnings = append(g.warnings, &Violation{Entry: name, Err: problem})
+		}
+	}
+
+	if other, ok := g.cases.Add(rel); ok {
+		g.warnings = append(g.warnings, &Violation{Entry: name, Err: fmt.Errorf("%w: %q", filesystem.ErrCaseCollision, other)})
+	}
+
+	return filepath.Join(g.dest, filepath.FromSlash(rel)), nil
+}
+
+// Entry counts an entry of the archive and checks the total size the entries claim to have. The claimed sizes are
+// summed apart from the bytes written through Copy, since backends that extract by themselves, such as 7-Zip,
+// only report entries.
+func (g *Guard) Entry(name string, size uint64) error {
+	g.entries++
+
+	if g.limits.MaxEntries > 0 && g.entries > g.limits.MaxEntries {
+		return &Violation{Entry: name, Err: ErrTooManyEntries}
+	}
+
+	g.claimed += min(size, math.MaxUint64-g.claimed)
+
+	return g.check(name, g.claimed)
+}
+
+// Skip records a link entry that is not extracted. Links could point outside the destination, and files written
+// through them would escape it, so they are never created.
+func (g *Guard) Skip(name string) {
+	g.warnings = append(g.warnings, &Violation{Entry: name, Err: ErrLink})
+}
+
+// Warnings returns the entries that were skipped, renamed or collide by case.
+func (g *Guard) Warnings() []error {
+	return g.warnings
+}
+
+// Copy copies the content of the entry name from src to dst, stopping once a limit is exceeded.
+func (g *Guard) Copy(name string, dst io.Writer, src io.Reader) error {
+	const step = 32 * 1024
+
+	for {
+		n, err := io.CopyN(dst, src, step)
+		g.total += uint64(n) //nolint:gosec // reason: n is not negative.
+
+		if violation := g.check(name, g.total); violation != nil {
+			return violation
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// check returns a violation if total exceeds the maximum size or ratio.
+func (g *Guard) check(name string, total uint64) error {
+	if g.limits.MaxSize > 0 && total > g.limits.MaxSize {
+		return &Violation{Entry: name, Err: ErrTooLarge}
+	}
+
+	if g.limits.MaxRatio > 0 && total > max(g.packed, ratioFloor)*g.limits.MaxRatio {
+		return &Violation{Entry: name, Err: ErrCompressionRatio}
+	}
+
+	return nil
+}
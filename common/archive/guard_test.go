@@ -0,0 +1,188 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package archive_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hkmh223/pd2mm/common/archive"
+	"github.com/hkmh223/pd2mm/common/filesystem"
+)
+
+func newGuard(t *testing.T, limits archive.Limits) (*archive.Guard, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "mod.zip")
+
+	if err := os.WriteFile(src, []byte("archive"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	guard, err := archive.NewGuard(src, filepath.Join(dir, "mod"), limits)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return guard, filepath.Join(dir, "mod")
+}
+
+func TestGuardPath(t *testing.T) {
+	t.Parallel()
+
+	guard, dest := newGuard(t, archive.Limits{MaxSize: 0, MaxRatio: 0, MaxEntries: 0})
+
+	for name, expected := range map[string]error{
+		"lua/a.lua":         nil,
+		"a/../b.lua":        nil,
+		"../evil.lua":       archive.ErrPathTraversal,
+		`lua\..\..\evil`:    archive.ErrPathTraversal,
+		"/etc/passwd":       archive.ErrAbsolutePath,
+		`\windows\evil.dll`: archive.ErrAbsolutePath,
+		"C:/evil.dll":       archive.ErrAbsolutePath,
+	} {
+		path, err := guard.Path(name)
+		if !errors.Is(err, expected) {
+			t.Fatalf("expected %v for %q, got %v", expected, name, err)
+		}
+
+		if err == nil && !strings.HasPrefix(path, dest+string(filepath.Separator)) {
+			t.Fatalf("%q is outside of %q", path, dest)
+		}
+	}
+}
+
+func TestJoin(t *testing.T) {
+	t.Parallel()
+
+	dest := t.TempDir()
+
+	for name, expected := range map[string]error{
+		"units/example/example.unit": nil,
+		"../../example.unit":         archive.ErrPathTraversal,
+		"/units/example.unit":        archive.ErrAbsolutePath,
+	} {
+		path, err := archive.Join(dest, name)
+		if !errors.Is(err, expected) {
+			t.Fatalf("expected %v for %q, got %v", expected, name, err)
+		}
+
+		if err == nil && path != filepath.Join(dest, filepath.FromSlash(name)) {
+			t.Fatalf("expected %q below %q, got %q", name, dest, path)
+		}
+	}
+}
+
+func TestGuardLimits(t *testing.T) {
+	t.Parallel()
+
+	guard, _ := newGuard(t, archive.Limits{MaxSize: 0, MaxRatio: 0, MaxEntries: 2})
+
+	for _, name := range []string{"a", "b"} {
+		if err := guard.Entry(name, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := guard.Entry("c", 0); !errors.Is(err, archive.ErrTooManyEntries) {
+		t.Fatalf("expected too many entries, got %v", err)
+	}
+
+	// the claimed size is checked, and so is the data actually written.
+	guard, _ = newGuard(t, archive.Limits{MaxSize: 1024, MaxRatio: 0, MaxEntries: 0})
+
+	if err := guard.Entry("big", 2048); !errors.Is(err, archive.ErrTooLarge) {
+		t.Fatalf("expected too large, got %v", err)
+	}
+
+	if err := guard.Copy("liar", io.Discard, bytes.NewReader(make([]byte, 2048))); !errors.Is(err, archive.ErrTooLarge) {
+		t.Fatalf("expected too large, got %v", err)
+	}
+
+	// entries under the limit on their own are summed, as 7-Zip only reports entries.
+	guard, _ = newGuard(t, archive.Limits{MaxSize: 1024, MaxRatio: 0, MaxEntries: 0})
+
+	for _, name := range []string{"a", "b"} {
+		if err := guard.Entry(name, 400); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := guard.Entry("c", 400); !errors.Is(err, archive.ErrTooLarge) {
+		t.Fatalf("expected the summed entries to be too large, got %v", err)
+	}
+
+	// the 7 byte archive is measured as 1 MiB, so it may expand to 2 MiB.
+	guard, _ = newGuard(t, archive.Limits{MaxSize: 0, MaxRatio: 2, MaxEntries: 0})
+
+	if err := guard.Copy("bomb", io.Discard, bytes.NewReader(make([]byte, 3<<20))); !errors.Is(err, archive.ErrCompressionRatio) {
+		t.Fatalf("expected compression ratio, got %v", err)
+	}
+}
+
+func TestGuardNames(t *testing.T) {
+	t.Parallel()
+
+	guard, dest := newGuard(t, archive.Limits{MaxSize: 0, MaxRatio: 0, MaxEntries: 0})
+	guard.SetNamePolicy(filesystem.NamePolicyRename)
+
+	for name, expected := range map[string]string{
+		"lua/a.lua":       "lua/a.lua",
+		"lua/what?.lua":   "lua/what_.lua",
+		"assets/CON.xml":  "assets/CON_.xml",
+		"guis/name. /a.x": "guis/name/a.x",
+	} {
+		path, err := guard.Path(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if path != filepath.Join(dest, filepath.FromSlash(expected)) {
+			t.Fatalf("expected %q for %q, got %q", expected, name, path)
+		}
+	}
+
+	if _, err := guard.Path("LUA/b.lua"); err != nil {
+		t.Fatal(err)
+	}
+
+	collisions := 0
+
+	for _, warning := range guard.Warnings() {
+		if errors.Is(warning, filesystem.ErrCaseCollision) {
+			collisions++
+		}
+	}
+
+	if len(guard.Warnings()) != 4 || collisions != 1 {
+		t.Fatalf("expected 3 renames and 1 case collision, got %v", guard.Warnings())
+	}
+
+	guard.SetNamePolicy(filesystem.NamePolicyReject)
+
+	if _, err := guard.Path("aux"); !errors.Is(err, filesystem.ErrReservedName) {
+		t.Fatalf("expected a reserved name, got %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package crypto
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/hkmh223/pd2mm/common/readwrite"
+)
+
+var ErrInvalidIDString = errors.New("invalid idstring hash")
+
+// Hashlist maps idstring hashes back to the strings they were computed from.
+type Hashlist struct {
+	Entries []readwrite.DataEntry
+	index   map[uint64]int
+}
+
+// NewHashlist creates a hashlist of the given strings. Empty strings and duplicates are ignored.
+func NewHashlist(lines []string) *Hashlist {
+	hashlist := &Hashlist{
+		Entries: make([]readwrite.DataEntry, 0, len(lines)),
+		index:   make(map[uint64]int, len(lines)),
+	}
+
+	for _, line := range lines {
+		hashlist.Add(line)
+	}
+
+	return hashlist
+}
+
+// ReadHashlist reads a hashlist file with one string per line.
+func ReadHashlist(name string) (*Hashlist, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	hashlist := NewHashlist(nil)
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		hashlist.Add(scanner.Text())
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return hashlist, nil
+}
+
+// Add hashes str and adds it to the hashlist.
+func (h *Hashlist) Add(str string) {
+	str = strings.TrimRight(str, "\r")
+	if str == "" {
+		return
+	}
+
+	hash := IDString(str)
+	if _, ok := h.index[hash]; ok {
+		return
+	}
+
+	h.index[hash] = len(h.Entries)
+	h.Entries = append(h.Entries, readwrite.DataEntry{Hash: hash, FileName: str})
+}
+
+// Lookup returns the string the hash was computed from.
+func (h *Hashlist) Lookup(hash uint64) (string, bool) {
+	i, ok := h.index[hash]
+	if !ok {
+		return "", false
+	}
+
+	return h.Entries[i].FileName, true
+}
+
+// FormatIDString formats hash the way idstrings are usually written, as 16 hexadecimal digits.
+func FormatIDString(hash uint64) string {
+	return fmt.Sprintf("%016x", hash)
+}
+
+// ParseIDString parses a hexadecimal idstring hash with an optional 0x prefix.
+func ParseIDString(str string) (uint64, error) {
+	str = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(str)), "0x")
+
+	hash, err := strconv.ParseUint(str, 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidIDString, str)
+	}
+
+	return hash, nil
+}
@@ -0,0 +1,109 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package crypto
+
+import (
+	"encoding/binary"
+)
+
+const (
+	lookup8Golden    uint64 = 0x9e3779b97f4a7c13
+	lookup8BlockSize int    = 24
+)
+
+// IDString returns the Diesel idstring hash of str, which is the lookup8 hash at level 0.
+func IDString(str string) uint64 {
+	return Lookup8([]byte(str), 0)
+}
+
+// Lookup8 returns the 64-bit hash of data as described by Bob Jenkins' lookup8.
+//
+//nolint:mnd // reason: hash function.
+func Lookup8(data []byte, level uint64) uint64 {
+	a, b, c := level, level, lookup8Golden
+	length := uint64(len(data))
+
+	for len(data) >= lookup8BlockSize {
+		a += binary.LittleEndian.Uint64(data[0:])
+		b += binary.LittleEndian.Uint64(data[8:])
+		c += binary.LittleEndian.Uint64(data[16:])
+		a, b, c = mix64(a, b, c)
+		data = data[lookup8BlockSize:]
+	}
+
+	c += length
+
+	// the lowest byte of c is reserved for the length.
+	for i, char := range data {
+		switch {
+		case i >= 16:
+			c += uint64(char) << (8 * (i - 15))
+		case i >= 8:
+			b += uint64(char) << (8 * (i - 8))
+		default:
+			a += uint64(char) << (8 * i)
+		}
+	}
+
+	_, _, c = mix64(a, b, c)
+
+	return c
+}
+
+//nolint:mnd // reason: hash function.
+func mix64(a, b, c uint64) (uint64, uint64, uint64) {
+	a -= b
+	a -= c
+	a ^= c >> 43
+	b -= c
+	b -= a
+	b ^= a << 9
+	c -= a
+	c -= b
+	c ^= b >> 8
+	a -= b
+	a -= c
+	a ^= c >> 38
+	b -= c
+	b -= a
+	b ^= a << 23
+	c -= a
+	c -= b
+	c ^= b >> 5
+	a -= b
+	a -= c
+	a ^= c >> 35
+	b -= c
+	b -= a
+	b ^= a << 49
+	c -= a
+	c -= b
+	c ^= b >> 11
+	a -= b
+	a -= c
+	a ^= c >> 12
+	b -= c
+	b -= a
+	b ^= a << 18
+	c -= a
+	c -= b
+	c ^= b >> 22
+
+	return a, b, c
+}
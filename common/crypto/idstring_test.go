@@ -0,0 +1,72 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package crypto_test
+
+import (
+	"testing"
+
+	"github.com/hkmh223/pd2mm/common/crypto"
+)
+
+func TestIDString(t *testing.T) {
+	t.Parallel()
+
+	hashes := map[string]uint64{
+		"":        0x8db63936938575bf,
+		"texture": 0x5368e150b05a5b8c,
+		"unit":    0x7dbca958ad01668f,
+		"lua":     0xab2664bf82e6460c,
+		"units/payday2/characters/ene_cop_1/ene_cop_1":              0x15f0b6e8b48b231d,
+		"a_string_that_is_longer_than_twenty_four_bytes_for_sure!!": 0xdab5c8b9f6f259c4,
+		"guis/textures/pd2/blackmarket/icons/weapons/amcar":         0xc63fa98dce20d724,
+	}
+
+	for str, hash := range hashes {
+		if got := crypto.IDString(str); got != hash {
+			t.Errorf("IDString(%q) = %016x, want %016x", str, got, hash)
+		}
+	}
+}
+
+func TestHashlist(t *testing.T) {
+	t.Parallel()
+
+	hashlist := crypto.NewHashlist([]string{"unit", "texture\r", "", "unit"})
+
+	if len(hashlist.Entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(hashlist.Entries))
+	}
+
+	hash, err := crypto.ParseIDString("0x5368E150B05A5B8C")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if str, ok := hashlist.Lookup(hash); !ok || str != "texture" {
+		t.Errorf("Lookup(%s) = %q, %t", crypto.FormatIDString(hash), str, ok)
+	}
+
+	if _, ok := hashlist.Lookup(crypto.IDString("lua")); ok {
+		t.Error("unexpected entry for lua")
+	}
+
+	if _, err := crypto.ParseIDString("not a hash"); err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -0,0 +1,201 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package diesel
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hkmh223/pd2mm/common/readwrite"
+)
+
+const (
+	HeaderSuffix = "_h.bundle"
+	BundleSuffix = ".bundle"
+)
+
+// BundleEntry is the location of an asset inside the payload of a bundle.
+type BundleEntry struct {
+	ID     uint32
+	Offset uint32
+	Length uint32
+}
+
+// BundleHeader is the table of contents of a bundle payload.
+type BundleHeader struct {
+	Payload string
+	Entries []BundleEntry
+
+	index map[uint32]int
+}
+
+// ReadBundleHeader reads the bundle header at name, the payload is expected next to it without the `_h` suffix.
+//
+// The header starts with a uint32 length followed by a section of a uint32 count, a uint32 capacity
+// and a uint64 offset relative to the end of the length. Entries are { uint32 id, uint32 offset } sorted by offset,
+// an entry ends where the next one starts or at the end of the payload.
+func ReadBundleHeader(name string) (*BundleHeader, error) {
+	reader, err := readwrite.NewReader(name)
+	if err != nil {
+		return nil, err
+	}
+
+	defer reader.Close()
+
+	payload := strings.TrimSuffix(name, HeaderSuffix) + BundleSuffix
+
+	info, err := os.Stat(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	size, err := reader.Size()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := reader.ReadUInt32(); err != nil {
+		return nil, err
+	}
+
+	entries, err := readSection(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	entries.offset += 4 //nolint:mnd // reason: offsets start after the header length.
+
+	if !fits(entries, bundleEntrySize, size) {
+		return nil, ErrInvalidBundle
+	}
+
+	header := &BundleHeader{
+		Payload: payload,
+		Entries: make([]BundleEntry, 0, entries.count),
+		index:   make(map[uint32]int, entries.count),
+	}
+
+	if _, err := reader.SeekFromBeginning(int64(entries.offset)); err != nil { //nolint:gosec // reason: offset is checked against the size.
+		return nil, err
+	}
+
+	for range entries.count {
+		id, err := reader.ReadUInt32()
+		if err != nil {
+			return nil, err
+		}
+
+		offset, err := reader.ReadUInt32()
+		if err != nil {
+			return nil, err
+		}
+
+		header.Entries = append(header.Entries, BundleEntry{ID: id, Offset: offset, Length: 0})
+	}
+
+	if err := header.setLengths(info.Size()); err != nil {
+		return nil, err
+	}
+
+	return header, nil
+}
+
+// ReadBundleHeaders reads every bundle header in the directory dir.
+func ReadBundleHeaders(dir string) ([]*BundleHeader, error) {
+	names, err := filepath.Glob(filepath.Join(dir, "*"+HeaderSuffix))
+	if err != nil {
+		return nil, err
+	}
+
+	slices.Sort(names)
+
+	headers := make([]*BundleHeader, 0, len(names))
+
+	for _, name := range names {
+		header, err := ReadBundleHeader(name)
+		if err != nil {
+			return nil, err
+		}
+
+		headers = append(headers, header)
+	}
+
+	return headers, nil
+}
+
+// Find returns the entry of the asset with the bundle database id.
+func (h *BundleHeader) Find(id uint32) (BundleEntry, bool) {
+	i, ok := h.index[id]
+	if !ok {
+		return BundleEntry{}, false
+	}
+
+	return h.Entries[i], true
+}
+
+// ReadEntry reads the data of entry from the payload.
+func (h *BundleHeader) ReadEntry(entry BundleEntry) ([]byte, error) {
+	reader, err := readwrite.NewReader(h.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	defer reader.Close()
+
+	if _, err := reader.SeekFromBeginning(int64(entry.Offset)); err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, entry.Length)
+	if _, err := io.ReadFull(reader, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// setLengths sets the length of every entry from the offset of the next entry and indexes the entries by id.
+func (h *BundleHeader) setLengths(size int64) error {
+	offsets := make([]uint32, 0, len(h.Entries)+1)
+	for _, entry := range h.Entries {
+		offsets = append(offsets, entry.Offset)
+	}
+
+	offsets = append(offsets, uint32(size)) //nolint:gosec // reason: payloads are smaller than 4 GiB.
+	slices.Sort(offsets)
+
+	for i, entry := range h.Entries {
+		if int64(entry.Offset) > size {
+			return ErrInvalidBundle
+		}
+
+		if next, _ := slices.BinarySearch(offsets, entry.Offset+1); next < len(offsets) {
+			h.Entries[i].Length = offsets[next] - entry.Offset
+		}
+
+		if _, ok := h.index[entry.ID]; !ok {
+			h.index[entry.ID] = i
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,238 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package diesel reads the file formats of the Diesel engine used by PAYDAY 2.
+package diesel
+
+import (
+	"strings"
+
+	"github.com/hkmh223/pd2mm/common/crypto"
+	"github.com/hkmh223/pd2mm/common/readwrite"
+)
+
+const (
+	BundleDBName = "bundle_db.blb"
+
+	languageEntrySize = 16
+	fileEntrySize     = 32
+	bundleEntrySize   = 8
+)
+
+// Language is a language entry of the bundle database.
+type Language struct {
+	Hash           uint64
+	Representation uint32
+}
+
+// File is a file entry of the bundle database. Extension and Path are idstring hashes.
+type File struct {
+	Extension uint64
+	Path      uint64
+	Language  uint32
+	ID        uint32
+}
+
+type fileKey struct {
+	path      uint64
+	extension uint64
+}
+
+// BundleDB is the index of every asset packed in the bundles of the game.
+type BundleDB struct {
+	Languages []Language
+	Files     []File
+
+	files      map[fileKey]int
+	extensions map[uint64]struct{}
+}
+
+type section struct {
+	count  uint32
+	offset uint64
+}
+
+// ReadBundleDB reads the bundle database at name.
+//
+// The database starts with three section headers made of a uint32 count, a uint32 capacity and a uint64 offset:
+// languages, an unknown section that is skipped and files.
+// Languages are { uint64 hash, uint32 representation, uint32 padding }.
+// Files are { uint64 extension, uint64 path, uint32 language, uint32 padding, uint32 id, uint32 padding }.
+func ReadBundleDB(name string) (*BundleDB, error) {
+	reader, err := readwrite.NewReader(name)
+	if err != nil {
+		return nil, err
+	}
+
+	defer reader.Close()
+
+	size, err := reader.Size()
+	if err != nil {
+		return nil, err
+	}
+
+	sections := make([]section, 3) //nolint:mnd // reason: languages, unknown and files.
+
+	for i := range sections {
+		if sections[i], err = readSection(reader); err != nil {
+			return nil, err
+		}
+	}
+
+	languages, files := sections[0], sections[2]
+
+	if !fits(languages, languageEntrySize, size) || !fits(files, fileEntrySize, size) {
+		return nil, ErrInvalidBundleDB
+	}
+
+	db := &BundleDB{
+		Languages:  make([]Language, 0, languages.count),
+		Files:      make([]File, 0, files.count),
+		files:      make(map[fileKey]int, files.count),
+		extensions: make(map[uint64]struct{}),
+	}
+
+	if err := db.readLanguages(reader, languages); err != nil {
+		return nil, err
+	}
+
+	if err := db.readFiles(reader, files); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// Contains returns true if the asset at path with the extension ext exists in the database.
+// The path is expected without extension, e.g. "units/payday2/characters/ene_cop_1/ene_cop_1".
+func (db *BundleDB) Contains(path, ext string) bool {
+	return db.ContainsHash(crypto.IDString(normalize(path)), crypto.IDString(normalize(ext)))
+}
+
+// ContainsHash returns true if the asset with the hashed path and extension exists in the database.
+func (db *BundleDB) ContainsHash(path, ext uint64) bool {
+	_, ok := db.files[fileKey{path: path, extension: ext}]
+
+	return ok
+}
+
+// Find returns the entry of the asset at path with the extension ext.
+func (db *BundleDB) Find(path, ext string) (File, bool) {
+	i, ok := db.files[fileKey{path: crypto.IDString(normalize(path)), extension: crypto.IDString(normalize(ext))}]
+	if !ok {
+		return File{}, false
+	}
+
+	return db.Files[i], true
+}
+
+// HasExtension returns true if at least one asset with the extension ext exists in the database.
+func (db *BundleDB) HasExtension(ext string) bool {
+	_, ok := db.extensions[crypto.IDString(normalize(ext))]
+
+	return ok
+}
+
+func (db *BundleDB) readLanguages(reader *readwrite.Reader, languages section) error {
+	if _, err := reader.SeekFromBeginning(int64(languages.offset)); err != nil { //nolint:gosec // reason: offset is checked against the size.
+		return err
+	}
+
+	for range languages.count {
+		hash, err := reader.ReadUInt64()
+		if err != nil {
+			return err
+		}
+
+		representation, err := reader.ReadUInt64()
+		if err != nil {
+			return err
+		}
+
+		db.Languages = append(db.Languages, Language{Hash: hash, Representation: uint32(representation)}) //nolint:gosec // reason: upper half is padding.
+	}
+
+	return nil
+}
+
+func (db *BundleDB) readFiles(reader *readwrite.Reader, files section) error {
+	if _, err := reader.SeekFromBeginning(int64(files.offset)); err != nil { //nolint:gosec // reason: offset is checked against the size.
+		return err
+	}
+
+	for range files.count {
+		var values [4]uint64
+
+		for i := range values {
+			value, err := reader.ReadUInt64()
+			if err != nil {
+				return err
+			}
+
+			values[i] = value
+		}
+
+		file := File{
+			Extension: values[0],
+			Path:      values[1],
+			Language:  uint32(values[2]), //nolint:gosec // reason: upper half is padding.
+			ID:        uint32(values[3]), //nolint:gosec // reason: upper half is padding.
+		}
+
+		key := fileKey{path: file.Path, extension: file.Extension}
+		if _, ok := db.files[key]; !ok {
+			db.files[key] = len(db.Files)
+		}
+
+		db.Files = append(db.Files, file)
+		db.extensions[file.Extension] = struct{}{}
+	}
+
+	return nil
+}
+
+func readSection(reader *readwrite.Reader) (section, error) {
+	count, err := reader.ReadUInt32()
+	if err != nil {
+		return section{}, err
+	}
+
+	if _, err := reader.ReadUInt32(); err != nil {
+		return section{}, err
+	}
+
+	offset, err := reader.ReadUInt64()
+	if err != nil {
+		return section{}, err
+	}
+
+	return section{count: count, offset: offset}, nil
+}
+
+// fits returns true if all entries of s are inside a file of the given size.
+func fits(s section, entrySize, size int64) bool {
+	if s.count == 0 {
+		return true
+	}
+
+	return s.offset <= uint64(size) && uint64(s.count)*uint64(entrySize) <= uint64(size)-s.offset //nolint:gosec // reason: size is positive.
+}
+
+func normalize(str string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimPrefix(str, "."), "\\", "/"))
+}
@@ -0,0 +1,148 @@
+package diesel_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hkmh223/pd2mm/common/crypto"
+	"github.com/hkmh223/pd2mm/common/diesel"
+)
+
+type asset struct {
+	path string
+	ext  string
+}
+
+func writeBundleDB(t *testing.T, assets []asset) string {
+	t.Helper()
+
+	const headers = 48
+
+	var buf bytes.Buffer
+
+	write := func(values ...any) {
+		for _, value := range values {
+			if err := binary.Write(&buf, binary.LittleEndian, value); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	write(uint32(1), uint32(1), uint64(headers))
+	write(uint32(0), uint32(0), uint64(0))
+	write(uint32(len(assets)), uint32(len(assets)), uint64(headers+16)) //nolint:gosec // reason: test data.
+	write(crypto.IDString("english"), uint32(0), uint32(0))
+
+	for i, asset := range assets {
+		write(crypto.IDString(asset.ext), crypto.IDString(asset.path), uint32(0), uint32(0), uint32(i), uint32(0)) //nolint:gosec // reason: test data.
+	}
+
+	name := filepath.Join(t.TempDir(), diesel.BundleDBName)
+	if err := os.WriteFile(name, buf.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return name
+}
+
+func TestReadBundleDB(t *testing.T) {
+	t.Parallel()
+
+	name := writeBundleDB(t, []asset{
+		{path: "units/payday2/characters/ene_cop_1/ene_cop_1", ext: "unit"},
+		{path: "guis/textures/pd2/blackmarket/icons/weapons/amcar", ext: "texture"},
+	})
+
+	db, err := diesel.ReadBundleDB(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(db.Languages) != 1 || len(db.Files) != 2 {
+		t.Fatalf("read %d languages and %d files", len(db.Languages), len(db.Files))
+	}
+
+	if !db.Contains("units/payday2/characters/ene_cop_1/ene_cop_1", "unit") {
+		t.Error("unit not found")
+	}
+
+	if !db.Contains("Guis\\Textures\\pd2\\blackmarket\\icons\\weapons\\amcar", ".texture") {
+		t.Error("texture not found")
+	}
+
+	if db.Contains("units/payday2/characters/ene_cop_1/ene_cop_1", "texture") {
+		t.Error("unexpected asset found")
+	}
+
+	if !db.HasExtension("texture") || db.HasExtension("lua") {
+		t.Error("unexpected extensions")
+	}
+}
+
+func TestReadBundleDBInvalid(t *testing.T) {
+	t.Parallel()
+
+	name := filepath.Join(t.TempDir(), diesel.BundleDBName)
+
+	data := make([]byte, 48)
+	binary.LittleEndian.PutUint32(data[32:], 1000)
+
+	if err := os.WriteFile(name, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := diesel.ReadBundleDB(name); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestReadBundleHeader(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	payload := []byte("firstsecond!")
+
+	var buf bytes.Buffer
+
+	for _, value := range []any{uint32(36), uint32(2), uint32(2), uint64(16), uint32(7), uint32(5), uint32(3), uint32(0)} {
+		if err := binary.Write(&buf, binary.LittleEndian, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "all_1"+diesel.HeaderSuffix), buf.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "all_1"+diesel.BundleSuffix), payload, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	headers, err := diesel.ReadBundleHeaders(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(headers) != 1 {
+		t.Fatalf("expected 1 header, got %d", len(headers))
+	}
+
+	for id, expected := range map[uint32]string{3: "first", 7: "second!"} {
+		entry, ok := headers[0].Find(id)
+		if !ok {
+			t.Fatalf("entry %d not found", id)
+		}
+
+		content, err := headers[0].ReadEntry(entry)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(content) != expected {
+			t.Errorf("entry %d = %q, want %q", id, content, expected)
+		}
+	}
+}
@@ -29,3 +29,8 @@ type MError struct {
 func (e *MError) Error() string {
 	return fmt.Sprintf("[%s] %s\n- %v", e.Header, e.Message, e.Err)
 }
+
+// Unwrap returns the wrapped error, so errors.Is and errors.As see through an MError.
+func (e *MError) Unwrap() error {
+	return e.Err
+}
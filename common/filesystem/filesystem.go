@@ -43,10 +43,12 @@ var (
 	ErrFileExists  = errors.New("file exists in destination path")
 )
 
+// ReservedHostnames are the device names Windows reserves. They can not be used as hostnames, and not as file or
+// directory names either, with or without an extension.
 var ReservedHostnames = []string{ //nolint:gochecknoglobals // reason: ReservedHostNames is constant.
-	"COM1", "COM2", "COM3", "COM4", "COM5", "COM6", "COM7", "COM8", "COM9",
-	"LPT1", "LPT2", "LPT3", "LPT4", "LPT5", "LPT6", "LPT7", "LPT8", "LPT9",
-	"PRN", "AUX", "NUL",
+	"CON", "PRN", "AUX", "NUL", "CLOCK$",
+	"COM0", "COM1", "COM2", "COM3", "COM4", "COM5", "COM6", "COM7", "COM8", "COM9",
+	"LPT0", "LPT1", "LPT2", "LPT3", "LPT4", "LPT5", "LPT6", "LPT7", "LPT8", "LPT9",
 }
 
 // Normalize replaces all backslashes with forward slashes in a string.
@@ -229,6 +231,42 @@ func Exists(name string) bool {
 	return !os.IsNotExist(err)
 }
 
+// Check if a path exists under root, matching each segment case-insensitively like Windows does.
+func ExistsFold(root, name string) bool {
+	current := root
+
+	for _, part := range ToNormalizedSlice(name) {
+		switch part {
+		case "", ".":
+			continue
+		case "..":
+			current = filepath.Dir(current)
+			continue
+		}
+
+		if Exists(filepath.Join(current, part)) {
+			current = filepath.Join(current, part)
+			continue
+		}
+
+		entries, err := os.ReadDir(current)
+		if err != nil {
+			return false
+		}
+
+		index := slices.IndexFunc(entries, func(entry os.DirEntry) bool {
+			return strings.EqualFold(entry.Name(), part)
+		})
+		if index == -1 {
+			return false
+		}
+
+		current = filepath.Join(current, entries[index].Name())
+	}
+
+	return true
+}
+
 // Read a file and return it as an array of bytes.
 func ReadFile(name string) ([]byte, error) {
 	file, err := os.ReadFile(name)
@@ -0,0 +1,176 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package filesystem
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Policies for names that can not be created on Windows.
+const (
+	NamePolicyRename string = "rename"
+	NamePolicyReject string = "reject"
+	NamePolicyKeep   string = "keep"
+)
+
+var (
+	ErrInvalidCharacter = errors.New("name contains a character that is invalid on Windows")
+	ErrTrailingDot      = errors.New("name ends with a dot or space, which Windows removes")
+	ErrReservedName     = errors.New("name is reserved on Windows")
+	ErrCaseCollision    = errors.New("name differs only by case from another name, they are merged on Windows")
+	ErrNamePolicy       = errors.New("unknown name policy, expected rename, reject or keep")
+)
+
+const invalidCharacters = `<>:"/\|?*`
+
+// CheckNamePolicy returns an error if policy is not one of the NamePolicy constants.
+func CheckNamePolicy(policy string) error {
+	switch policy {
+	case NamePolicyRename, NamePolicyReject, NamePolicyKeep:
+		return nil
+	}
+
+	return fmt.Errorf("%w: %q", ErrNamePolicy, policy)
+}
+
+// CheckName returns why the file or directory name can not be created on Windows, or nil if it can.
+func CheckName(name string) error {
+	if strings.ContainsFunc(name, isInvalidRune) {
+		return ErrInvalidCharacter
+	}
+
+	if name != "." && name != ".." && strings.TrimRight(name, ". ") != name {
+		return ErrTrailingDot
+	}
+
+	if isReserved(name) {
+		return ErrReservedName
+	}
+
+	return nil
+}
+
+// SanitizeName returns name fixed for Windows. Invalid characters become '_', trailing dots and spaces are removed
+// and reserved names get a '_' appended, so "CON.txt" becomes "CON_.txt".
+func SanitizeName(name string) string {
+	if name == "." || name == ".." {
+		return name
+	}
+
+	name = strings.Map(func(r rune) rune {
+		if isInvalidRune(r) {
+			return '_'
+		}
+
+		return r
+	}, name)
+
+	name = strings.TrimRight(name, ". ")
+	if name == "" {
+		return "_"
+	}
+
+	if isReserved(name) {
+		stem, ext, _ := strings.Cut(name, ".")
+		if ext != "" {
+			ext = "." + ext
+		}
+
+		name = stem + "_" + ext
+	}
+
+	return name
+}
+
+// SanitizePath applies SanitizeName to every element of the slash separated path.
+// The first problem found is returned with the sanitized path.
+func SanitizePath(path string) (string, error) {
+	var problem error
+
+	parts := strings.Split(path, "/")
+
+	for i, part := range parts {
+		err := CheckName(part)
+		if err == nil || part == "" {
+			continue
+		}
+
+		if problem == nil {
+			problem = err
+		}
+
+		parts[i] = SanitizeName(part)
+	}
+
+	return strings.Join(parts, "/"), problem
+}
+
+// CaseCollisions finds paths that differ only by case, which are the same path on Windows.
+type CaseCollisions struct {
+	seen     map[string]string
+	reported map[string]bool
+}
+
+// NewCaseCollisions creates an empty CaseCollisions.
+func NewCaseCollisions() *CaseCollisions {
+	return &CaseCollisions{seen: map[string]string{}, reported: map[string]bool{}}
+}
+
+// Add adds the slash separated path and its parent directories. It returns the path added before that differs
+// only by case, every collision is only returned once.
+func (c *CaseCollisions) Add(path string) (string, bool) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+
+	for i := range parts {
+		current := strings.Join(parts[:i+1], "/")
+		key := strings.ToLower(current)
+
+		seen, ok := c.seen[key]
+		if !ok {
+			c.seen[key] = current
+			continue
+		}
+
+		if seen != current && !c.reported[key+"\x00"+current] {
+			c.reported[key+"\x00"+current] = true
+			return seen, true
+		}
+	}
+
+	return "", false
+}
+
+func isInvalidRune(r rune) bool {
+	return r < ' ' || strings.ContainsRune(invalidCharacters, r)
+}
+
+func isReserved(name string) bool {
+	stem, _, _ := strings.Cut(name, ".")
+	stem = strings.TrimRight(stem, " ")
+
+	for _, reserved := range ReservedHostnames {
+		if strings.EqualFold(stem, reserved) {
+			return true
+		}
+	}
+
+	return false
+}
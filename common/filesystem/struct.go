@@ -35,7 +35,7 @@ const (
 
 // Default list of problematic paths.
 func DefaultProblemPaths() []PathCheck {
-	return []PathCheck{
+	checks := []PathCheck{
 		{Type: PathCheckTypeEndsWith, Target: "SteamApps", Action: PathCheckActionWarn},
 		{Type: PathCheckTypeEndsWith, Target: "Documents", Action: PathCheckActionWarn},
 		{Type: PathCheckTypeEndsWith, Target: "Desktop", Action: PathCheckActionDeny},
@@ -50,32 +50,12 @@ func DefaultProblemPaths() []PathCheck {
 		{Type: PathCheckTypeContains, Target: "Program Files (x86)", Action: PathCheckActionDeny},
 		// {Type: PathCheckTypeContains, Target: "Windows", Action: PathCheckActionDeny},
 		{Type: PathCheckTypeDriveRoot, Target: "", Action: PathCheckActionDeny},
+	}
 
-		// Reserved words
-		{Type: PathCheckTypeEndsWith, Target: "CON", Action: PathCheckActionDeny},
-		{Type: PathCheckTypeEndsWith, Target: "PRN", Action: PathCheckActionDeny},
-		{Type: PathCheckTypeEndsWith, Target: "AUX", Action: PathCheckActionDeny},
-		{Type: PathCheckTypeEndsWith, Target: "CLOCK$", Action: PathCheckActionDeny},
-		{Type: PathCheckTypeEndsWith, Target: "NUL", Action: PathCheckActionDeny},
-		{Type: PathCheckTypeEndsWith, Target: "COM0", Action: PathCheckActionDeny},
-		{Type: PathCheckTypeEndsWith, Target: "COM1", Action: PathCheckActionDeny},
-		{Type: PathCheckTypeEndsWith, Target: "COM2", Action: PathCheckActionDeny},
-		{Type: PathCheckTypeEndsWith, Target: "COM3", Action: PathCheckActionDeny},
-		{Type: PathCheckTypeEndsWith, Target: "COM4", Action: PathCheckActionDeny},
-		{Type: PathCheckTypeEndsWith, Target: "COM5", Action: PathCheckActionDeny},
-		{Type: PathCheckTypeEndsWith, Target: "COM6", Action: PathCheckActionDeny},
-		{Type: PathCheckTypeEndsWith, Target: "COM7", Action: PathCheckActionDeny},
-		{Type: PathCheckTypeEndsWith, Target: "COM8", Action: PathCheckActionDeny},
-		{Type: PathCheckTypeEndsWith, Target: "COM9", Action: PathCheckActionDeny},
-		{Type: PathCheckTypeEndsWith, Target: "LPT0", Action: PathCheckActionDeny},
-		{Type: PathCheckTypeEndsWith, Target: "LPT1", Action: PathCheckActionDeny},
-		{Type: PathCheckTypeEndsWith, Target: "LPT2", Action: PathCheckActionDeny},
-		{Type: PathCheckTypeEndsWith, Target: "LPT3", Action: PathCheckActionDeny},
-		{Type: PathCheckTypeEndsWith, Target: "LPT4", Action: PathCheckActionDeny},
-		{Type: PathCheckTypeEndsWith, Target: "LPT5", Action: PathCheckActionDeny},
-		{Type: PathCheckTypeEndsWith, Target: "LPT6", Action: PathCheckActionDeny},
-		{Type: PathCheckTypeEndsWith, Target: "LPT7", Action: PathCheckActionDeny},
-		{Type: PathCheckTypeEndsWith, Target: "LPT8", Action: PathCheckActionDeny},
-		{Type: PathCheckTypeEndsWith, Target: "LPT9", Action: PathCheckActionDeny},
+	// Reserved words
+	for _, name := range ReservedHostnames {
+		checks = append(checks, PathCheck{Type: PathCheckTypeEndsWith, Target: name, Action: PathCheckActionDeny})
 	}
+
+	return checks
 }
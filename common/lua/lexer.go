@@ -0,0 +1,343 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package lua
+
+import (
+	"fmt"
+	"strings"
+)
+
+type tokenType int
+
+const (
+	tokenEOF tokenType = iota
+	tokenName
+	tokenNumber
+	tokenString
+	tokenKeyword
+	tokenSymbol
+)
+
+type token struct {
+	kind  tokenType
+	value string
+	line  int
+}
+
+type SyntaxError struct {
+	Line    int
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+//nolint:gochecknoglobals // reason: keywords are constant.
+var keywords = map[string]bool{
+	"and": true, "break": true, "do": true, "else": true, "elseif": true, "end": true,
+	"false": true, "for": true, "function": true, "goto": true, "if": true, "in": true,
+	"local": true, "nil": true, "not": true, "or": true, "repeat": true, "return": true,
+	"then": true, "true": true, "until": true, "while": true,
+}
+
+// Symbols ordered so that longer symbols are matched first.
+//
+//nolint:gochecknoglobals // reason: symbols are constant.
+var symbols = []string{
+	"...", "..", "==", "~=", "<=", ">=", "::",
+	"+", "-", "*", "/", "%", "^", "#", "<", ">", "=",
+	"(", ")", "{", "}", "[", "]", ";", ":", ",", ".",
+}
+
+type lexer struct {
+	src  string
+	pos  int
+	line int
+}
+
+// String returns the token the way Lua refers to it in error messages.
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "<eof>"
+	}
+
+	return t.value
+}
+
+// next returns the next token of the source.
+func (l *lexer) next() (token, error) {
+	if err := l.skipWhitespace(); err != nil {
+		return token{}, err
+	}
+
+	if l.pos >= len(l.src) {
+		return token{kind: tokenEOF, value: "", line: l.line}, nil
+	}
+
+	char := l.src[l.pos]
+
+	switch {
+	case isNameStart(char):
+		return l.name(), nil
+	case isDigit(char) || (char == '.' && l.pos+1 < len(l.src) && isDigit(l.src[l.pos+1])):
+		return l.number()
+	case char == '"' || char == '\'':
+		return l.shortString(char)
+	case char == '[' && l.longBracketLevel() >= 0:
+		return l.longString()
+	}
+
+	for _, symbol := range symbols {
+		if strings.HasPrefix(l.src[l.pos:], symbol) {
+			l.pos += len(symbol)
+			return token{kind: tokenSymbol, value: symbol, line: l.line}, nil
+		}
+	}
+
+	return token{}, &SyntaxError{Line: l.line, Message: fmt.Sprintf("unexpected symbol near '%c'", char)}
+}
+
+// skipWhitespace skips whitespace, comments and a leading shebang line.
+func (l *lexer) skipWhitespace() error {
+	if l.pos == 0 && strings.HasPrefix(l.src, "#") {
+		l.skipLine()
+	}
+
+	for l.pos < len(l.src) {
+		switch char := l.src[l.pos]; {
+		case char == '\n':
+			l.line++
+			l.pos++
+		case char == ' ' || char == '\t' || char == '\r' || char == '\f' || char == '\v':
+			l.pos++
+		case strings.HasPrefix(l.src[l.pos:], "--"):
+			l.pos += 2
+
+			if l.pos < len(l.src) && l.src[l.pos] == '[' && l.longBracketLevel() >= 0 {
+				if _, err := l.longString(); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			l.skipLine()
+		default:
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// skipSpace skips the 'z' of a \z escape and the whitespace after it, including line breaks.
+func (l *lexer) skipSpace() {
+	for l.pos++; l.pos < len(l.src) && strings.IndexByte(" \t\r\n\f\v", l.src[l.pos]) >= 0; l.pos++ {
+		if l.src[l.pos] == '\n' {
+			l.line++
+		}
+	}
+}
+
+// skipLine moves the position to the next line break.
+func (l *lexer) skipLine() {
+	for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+		l.pos++
+	}
+}
+
+// name reads a name or keyword.
+func (l *lexer) name() token {
+	start := l.pos
+
+	for l.pos < len(l.src) && (isNameStart(l.src[l.pos]) || isDigit(l.src[l.pos])) {
+		l.pos++
+	}
+
+	value := l.src[start:l.pos]
+	if keywords[value] {
+		return token{kind: tokenKeyword, value: value, line: l.line}
+	}
+
+	return token{kind: tokenName, value: value, line: l.line}
+}
+
+// number reads a numeric literal, including the LuaJIT suffixes LL, ULL and i.
+func (l *lexer) number() (token, error) {
+	start := l.pos
+	exponent := "eE"
+
+	if strings.HasPrefix(strings.ToLower(l.src[l.pos:]), "0x") {
+		l.pos += 2
+		exponent = "pP"
+	}
+
+	for l.pos < len(l.src) {
+		char := l.src[l.pos]
+
+		switch {
+		case strings.IndexByte(exponent, char) >= 0 && l.pos+1 < len(l.src) && strings.IndexByte("+-", l.src[l.pos+1]) >= 0:
+			l.pos += 2
+		case isNameStart(char) || isDigit(char) || char == '.':
+			l.pos++
+		default:
+			return l.checkNumber(start)
+		}
+	}
+
+	return l.checkNumber(start)
+}
+
+// checkNumber validates the numeric literal that starts at start and ends at the current position.
+func (l *lexer) checkNumber(start int) (token, error) {
+	value := l.src[start:l.pos]
+	trimmed := strings.ToLower(value)
+
+	for _, suffix := range []string{"ull", "ll", "i"} {
+		if strings.HasSuffix(trimmed, suffix) {
+			trimmed = strings.TrimSuffix(trimmed, suffix)
+			break
+		}
+	}
+
+	if !isNumber(trimmed) {
+		return token{}, &SyntaxError{Line: l.line, Message: fmt.Sprintf("malformed number near '%s'", value)}
+	}
+
+	return token{kind: tokenNumber, value: value, line: l.line}, nil
+}
+
+// shortString reads a string delimited by quote.
+func (l *lexer) shortString(quote byte) (token, error) {
+	start, line := l.pos, l.line
+	l.pos++
+
+	for l.pos < len(l.src) {
+		switch l.src[l.pos] {
+		case quote:
+			l.pos++
+			return token{kind: tokenString, value: l.src[start:l.pos], line: line}, nil
+		case '\n':
+			return token{}, &SyntaxError{Line: l.line, Message: fmt.Sprintf("unfinished string near '%s'", l.src[start:l.pos])}
+		case '\\':
+			l.pos++
+
+			if l.pos < len(l.src) && l.src[l.pos] == 'z' {
+				l.skipSpace()
+				continue
+			}
+
+			if l.pos < len(l.src) && l.src[l.pos] == '\n' {
+				l.line++
+			}
+		}
+
+		l.pos++
+	}
+
+	return token{}, &SyntaxError{Line: l.line, Message: "unfinished string near '<eof>'"}
+}
+
+// longBracketLevel returns the level of the long bracket at the current position, or -1 if there is none.
+func (l *lexer) longBracketLevel() int {
+	index := l.pos + 1
+	for index < len(l.src) && l.src[index] == '=' {
+		index++
+	}
+
+	if index < len(l.src) && l.src[index] == '[' {
+		return index - l.pos - 1
+	}
+
+	return -1
+}
+
+// longString reads a long string or long comment such as [[...]] or [==[...]==].
+func (l *lexer) longString() (token, error) {
+	start, line := l.pos, l.line
+	level := l.longBracketLevel()
+	closing := "]" + strings.Repeat("=", level) + "]"
+
+	l.pos += level + 2 //nolint:mnd // reason: skip both opening brackets.
+
+	end := strings.Index(l.src[l.pos:], closing)
+	if end == -1 {
+		l.line += strings.Count(l.src[l.pos:], "\n")
+		l.pos = len(l.src)
+
+		return token{}, &SyntaxError{Line: l.line, Message: "unfinished long string near '<eof>'"}
+	}
+
+	l.line += strings.Count(l.src[l.pos:l.pos+end], "\n")
+	l.pos += end + len(closing)
+
+	return token{kind: tokenString, value: l.src[start:l.pos], line: line}, nil
+}
+
+// isNameStart returns true if char can start a name.
+func isNameStart(char byte) bool {
+	return char == '_' || (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z')
+}
+
+// isDigit returns true if char is a decimal digit.
+func isDigit(char byte) bool {
+	return char >= '0' && char <= '9'
+}
+
+// isNumber returns true if str is a valid decimal or hexadecimal number.
+func isNumber(str string) bool {
+	if hex, ok := strings.CutPrefix(str, "0x"); ok {
+		mantissa, exponent, hasExponent := strings.Cut(hex, "p")
+		if hasExponent && !isDigits(strings.TrimLeft(exponent, "+-"), "0123456789") {
+			return false
+		}
+
+		return isFraction(mantissa, "0123456789abcdef")
+	}
+
+	mantissa, exponent, hasExponent := strings.Cut(str, "e")
+	if hasExponent && !isDigits(strings.TrimLeft(exponent, "+-"), "0123456789") {
+		return false
+	}
+
+	return isFraction(mantissa, "0123456789")
+}
+
+// isFraction returns true if str is made of digits with at most one decimal point.
+func isFraction(str, digits string) bool {
+	whole, fraction, _ := strings.Cut(str, ".")
+
+	return whole+fraction != "" && (whole == "" || isDigits(whole, digits)) && (fraction == "" || isDigits(fraction, digits))
+}
+
+// isDigits returns true if str is not empty and only contains characters of digits.
+func isDigits(str, digits string) bool {
+	if str == "" {
+		return false
+	}
+
+	for _, char := range str {
+		if !strings.ContainsRune(digits, char) {
+			return false
+		}
+	}
+
+	return true
+}
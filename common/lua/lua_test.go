@@ -0,0 +1,119 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package lua_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hkmh223/pd2mm/common/lua"
+)
+
+const validSource = `#!/usr/bin/env lua
+-- comment
+--[==[ long
+comment ]==]
+local Example = Example or class(Base)
+Example.values = { 1, 2.5, 0x1F, 1e-3, 0x1p4, 10ULL, [3] = "three"; name = 'name', nested = { [[long]] } }
+
+function Example:init(...)
+	local a, b = ...
+	self._value = a and b or not a and #self.values or -1
+	for i = 1, 10, 2 do if i > 5 then break end end
+	for key, value in pairs(self.values) do print(key .. value) end
+	while false do end
+	repeat local x = 1 until x == 1
+	goto done
+	::done::
+	return self
+end
+
+Hooks:PostHook(MenuManager, "init", "Example", function(self) log("example") end)
+print "string call"
+setmetatable({}, {})
+`
+
+func TestCheckValid(t *testing.T) {
+	t.Parallel()
+
+	if err := lua.Check([]byte(validSource)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCheckInvalid(t *testing.T) {
+	t.Parallel()
+
+	sources := map[string]int{
+		"local a = ":                    1,
+		"function test()\n\nprint(1)\n": 4,
+		"if a then\nelse\nelse\nend":    3,
+		"x = 'unfinished\n'":            1,
+		"a.b() = 1":                     1,
+		"local t = {1, 2\nprint(t)":     2,
+		"return 1\nprint(2)":            2,
+		"x = 1..2":                      1,
+		"f(":                            1,
+		"x = [[never closed":            1,
+	}
+
+	for source, line := range sources {
+		err := lua.Check([]byte(source))
+
+		var syntaxErr *lua.SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Fatalf("expected a syntax error for %q, got %v", source, err)
+		}
+
+		if syntaxErr.Line != line {
+			t.Fatalf("expected %q to fail on line %d, got %v", source, line, syntaxErr)
+		}
+	}
+}
+
+func TestCheckPrefix(t *testing.T) {
+	t.Parallel()
+
+	sources := []string{
+		"\xEF\xBB\xBFlocal a = 1",
+		"\xEF\xBB\xBF#!/usr/bin/env lua\nlocal a = 1",
+		"#!/usr/bin/env luajit\r\nlocal a = 1",
+	}
+
+	for _, source := range sources {
+		if err := lua.Check([]byte(source)); err != nil {
+			t.Fatalf("expected %q to be valid, got %v", source, err)
+		}
+	}
+}
+
+func TestCheckSkipEscape(t *testing.T) {
+	t.Parallel()
+
+	if err := lua.Check([]byte("local a = \"first \\z\n\t\tsecond\"\nlocal b = 'c\\z  d'")); err != nil {
+		t.Fatal(err)
+	}
+
+	err := lua.Check([]byte("local a = \"first \\z\n\n\tsecond\"\nlocal b = ("))
+
+	var syntaxErr *lua.SyntaxError
+	if !errors.As(err, &syntaxErr) || syntaxErr.Line != 4 {
+		t.Fatalf("expected a syntax error on line 4, got %v", err)
+	}
+}
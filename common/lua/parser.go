@@ -0,0 +1,621 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package lua checks the syntax of Lua 5.1 source files as loaded by the LuaJIT runtime of PAYDAY 2.
+// LuaJIT extensions commonly used by mods, such as goto, labels and 64-bit integer literals, are accepted.
+package lua
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// bom is the UTF-8 byte order mark some editors write at the start of a file.
+const bom = "\xEF\xBB\xBF"
+
+type expressionKind int
+
+const (
+	kindOther expressionKind = iota
+	kindVariable
+	kindCall
+)
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// Check parses src and returns the first syntax error, or nil if src is a valid chunk.
+// A leading UTF-8 byte order mark is skipped like LuaJIT does, and so is a shebang line after it.
+func Check(src []byte) error {
+	lex := &lexer{src: strings.TrimPrefix(string(src), bom), pos: 0, line: 1}
+	parse := &parser{tokens: nil, pos: 0}
+
+	for {
+		tok, err := lex.next()
+		if err != nil {
+			return err
+		}
+
+		parse.tokens = append(parse.tokens, tok)
+
+		if tok.kind == tokenEOF {
+			break
+		}
+	}
+
+	if err := parse.block(); err != nil {
+		return err
+	}
+
+	if parse.current().kind != tokenEOF {
+		return parse.errorNear("'<eof>' expected")
+	}
+
+	return nil
+}
+
+// CheckFile reads the file at name and returns the first syntax error in it.
+func CheckFile(name string) error {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return err
+	}
+
+	return Check(data)
+}
+
+// current returns the current token.
+func (p *parser) current() token {
+	return p.tokens[p.pos]
+}
+
+// peek returns the token after the current token.
+func (p *parser) peek() token {
+	if p.pos+1 < len(p.tokens) {
+		return p.tokens[p.pos+1]
+	}
+
+	return p.tokens[len(p.tokens)-1]
+}
+
+// advance moves to the next token and returns the previous one.
+func (p *parser) advance() token {
+	tok := p.current()
+
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+
+	return tok
+}
+
+// check returns true if the current token is the keyword or symbol value.
+func (p *parser) check(value string) bool {
+	tok := p.current()
+	return (tok.kind == tokenKeyword || tok.kind == tokenSymbol) && tok.value == value
+}
+
+// accept advances if the current token is the keyword or symbol value.
+func (p *parser) accept(value string) bool {
+	if p.check(value) {
+		p.advance()
+		return true
+	}
+
+	return false
+}
+
+// expect advances past value or returns an error if the current token is not value.
+func (p *parser) expect(value string) error {
+	if !p.accept(value) {
+		return p.errorNear(fmt.Sprintf("'%s' expected", value))
+	}
+
+	return nil
+}
+
+// expectMatch advances past value, the error names the token opened at line if it is missing.
+func (p *parser) expectMatch(value, opener string, line int) error {
+	if p.accept(value) {
+		return nil
+	}
+
+	if line == p.current().line {
+		return p.errorNear(fmt.Sprintf("'%s' expected", value))
+	}
+
+	return p.errorNear(fmt.Sprintf("'%s' expected (to close '%s' at line %d)", value, opener, line))
+}
+
+// expectName advances past a name or returns an error if the current token is not a name.
+func (p *parser) expectName() error {
+	if p.current().kind != tokenName {
+		return p.errorNear("<name> expected")
+	}
+
+	p.advance()
+
+	return nil
+}
+
+// errorNear returns a syntax error at the current token.
+func (p *parser) errorNear(message string) error {
+	return &SyntaxError{Line: p.current().line, Message: fmt.Sprintf("%s near '%s'", message, p.current())}
+}
+
+// blockFollow returns true if the current token ends a block.
+func (p *parser) blockFollow() bool {
+	return p.current().kind == tokenEOF || p.check("else") || p.check("elseif") || p.check("end") || p.check("until")
+}
+
+// block parses statements until the end of the block, a return statement must be the last one.
+func (p *parser) block() error {
+	for !p.blockFollow() {
+		if p.accept("return") {
+			if !p.blockFollow() && !p.check(";") {
+				if err := p.expressionList(); err != nil {
+					return err
+				}
+			}
+
+			p.accept(";")
+
+			return nil
+		}
+
+		if err := p.statement(); err != nil {
+			return err
+		}
+
+		p.accept(";")
+	}
+
+	return nil
+}
+
+// statement parses a single statement.
+//
+//nolint:cyclop // reason: one case per statement type.
+func (p *parser) statement() error {
+	line := p.current().line
+
+	switch {
+	case p.check("if"):
+		return p.ifStatement()
+	case p.check("for"):
+		return p.forStatement()
+	case p.accept("while"):
+		if err := p.expression(); err != nil {
+			return err
+		}
+
+		if err := p.expect("do"); err != nil {
+			return err
+		}
+
+		return p.blockEnd("while", line)
+	case p.accept("do"):
+		return p.blockEnd("do", line)
+	case p.accept("repeat"):
+		if err := p.block(); err != nil {
+			return err
+		}
+
+		if err := p.expectMatch("until", "repeat", line); err != nil {
+			return err
+		}
+
+		return p.expression()
+	case p.accept("function"):
+		return p.functionStatement(line)
+	case p.accept("local"):
+		return p.localStatement()
+	case p.accept("::"):
+		if err := p.expectName(); err != nil {
+			return err
+		}
+
+		return p.expect("::")
+	case p.accept("goto"):
+		return p.expectName()
+	case p.accept("break"):
+		return nil
+	}
+
+	return p.expressionStatement()
+}
+
+// blockEnd parses a block closed by 'end'.
+func (p *parser) blockEnd(opener string, line int) error {
+	if err := p.block(); err != nil {
+		return err
+	}
+
+	return p.expectMatch("end", opener, line)
+}
+
+// ifStatement parses if ... then ... {elseif ... then ...} [else ...] end.
+func (p *parser) ifStatement() error {
+	line := p.advance().line
+
+	for {
+		if err := p.expression(); err != nil {
+			return err
+		}
+
+		if err := p.expect("then"); err != nil {
+			return err
+		}
+
+		if err := p.block(); err != nil {
+			return err
+		}
+
+		if !p.accept("elseif") {
+			break
+		}
+	}
+
+	if p.accept("else") {
+		if err := p.block(); err != nil {
+			return err
+		}
+	}
+
+	return p.expectMatch("end", "if", line)
+}
+
+// forStatement parses both the numeric and the generic for statement.
+func (p *parser) forStatement() error {
+	line := p.advance().line
+
+	if err := p.expectName(); err != nil {
+		return err
+	}
+
+	switch {
+	case p.accept("="):
+		if err := p.expression(); err != nil {
+			return err
+		}
+
+		if err := p.expect(","); err != nil {
+			return err
+		}
+
+		if err := p.expressionList(); err != nil {
+			return err
+		}
+	case p.check(",") || p.check("in"):
+		for p.accept(",") {
+			if err := p.expectName(); err != nil {
+				return err
+			}
+		}
+
+		if err := p.expect("in"); err != nil {
+			return err
+		}
+
+		if err := p.expressionList(); err != nil {
+			return err
+		}
+	default:
+		return p.errorNear("'=' or 'in' expected")
+	}
+
+	if err := p.expect("do"); err != nil {
+		return err
+	}
+
+	return p.blockEnd("for", line)
+}
+
+// functionStatement parses function a.b.c:d(...) ... end.
+func (p *parser) functionStatement(line int) error {
+	if err := p.expectName(); err != nil {
+		return err
+	}
+
+	for p.accept(".") {
+		if err := p.expectName(); err != nil {
+			return err
+		}
+	}
+
+	if p.accept(":") {
+		if err := p.expectName(); err != nil {
+			return err
+		}
+	}
+
+	return p.functionBody(line)
+}
+
+// localStatement parses local function and local variable declarations.
+func (p *parser) localStatement() error {
+	if p.check("function") {
+		line := p.advance().line
+
+		if err := p.expectName(); err != nil {
+			return err
+		}
+
+		return p.functionBody(line)
+	}
+
+	for {
+		if err := p.expectName(); err != nil {
+			return err
+		}
+
+		if !p.accept(",") {
+			break
+		}
+	}
+
+	if p.accept("=") {
+		return p.expressionList()
+	}
+
+	return nil
+}
+
+// expressionStatement parses a function call or an assignment.
+func (p *parser) expressionStatement() error {
+	kind, err := p.suffixedExpression()
+	if err != nil {
+		return err
+	}
+
+	if !p.check("=") && !p.check(",") {
+		if kind != kindCall {
+			return p.errorNear("syntax error")
+		}
+
+		return nil
+	}
+
+	for {
+		if kind != kindVariable {
+			return p.errorNear("syntax error")
+		}
+
+		if !p.accept(",") {
+			break
+		}
+
+		if kind, err = p.suffixedExpression(); err != nil {
+			return err
+		}
+	}
+
+	if err := p.expect("="); err != nil {
+		return err
+	}
+
+	return p.expressionList()
+}
+
+// functionBody parses (parameters) block end.
+func (p *parser) functionBody(line int) error {
+	if err := p.expect("("); err != nil {
+		return err
+	}
+
+	if !p.check(")") {
+		for {
+			if p.accept("...") {
+				break
+			}
+
+			if err := p.expectName(); err != nil {
+				return err
+			}
+
+			if !p.accept(",") {
+				break
+			}
+		}
+	}
+
+	if err := p.expect(")"); err != nil {
+		return err
+	}
+
+	return p.blockEnd("function", line)
+}
+
+// expressionList parses one or more expressions separated by commas.
+func (p *parser) expressionList() error {
+	for {
+		if err := p.expression(); err != nil {
+			return err
+		}
+
+		if !p.accept(",") {
+			return nil
+		}
+	}
+}
+
+// expression parses operands separated by binary operators, operator precedence does not affect validity.
+func (p *parser) expression() error {
+	for {
+		for p.accept("not") || p.accept("-") || p.accept("#") {
+			continue
+		}
+
+		if err := p.simpleExpression(); err != nil {
+			return err
+		}
+
+		if !p.binaryOperator() {
+			return nil
+		}
+
+		p.advance()
+	}
+}
+
+// binaryOperator returns true if the current token is a binary operator.
+func (p *parser) binaryOperator() bool {
+	for _, operator := range []string{"+", "-", "*", "/", "%", "^", "..", "==", "~=", "<", "<=", ">", ">=", "and", "or"} {
+		if p.check(operator) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// simpleExpression parses a literal, a table, an anonymous function or a suffixed expression.
+func (p *parser) simpleExpression() error {
+	switch tok := p.current(); {
+	case tok.kind == tokenNumber || tok.kind == tokenString:
+		p.advance()
+	case p.accept("nil") || p.accept("true") || p.accept("false") || p.accept("..."):
+	case p.check("{"):
+		return p.table()
+	case p.check("function"):
+		return p.functionBody(p.advance().line)
+	default:
+		_, err := p.suffixedExpression()
+		return err
+	}
+
+	return nil
+}
+
+// suffixedExpression parses a name or parenthesized expression followed by fields, indexes and calls.
+func (p *parser) suffixedExpression() (expressionKind, error) {
+	var kind expressionKind
+
+	switch line := p.current().line; {
+	case p.current().kind == tokenName:
+		p.advance()
+
+		kind = kindVariable
+	case p.accept("("):
+		if err := p.expression(); err != nil {
+			return kindOther, err
+		}
+
+		if err := p.expectMatch(")", "(", line); err != nil {
+			return kindOther, err
+		}
+
+		kind = kindOther
+	default:
+		return kindOther, p.errorNear("unexpected symbol")
+	}
+
+	for {
+		var err error
+
+		switch line := p.current().line; {
+		case p.accept("."):
+			kind = kindVariable
+			err = p.expectName()
+		case p.accept("["):
+			kind = kindVariable
+
+			if err = p.expression(); err == nil {
+				err = p.expectMatch("]", "[", line)
+			}
+		case p.accept(":"):
+			kind = kindCall
+
+			if err = p.expectName(); err == nil {
+				err = p.arguments()
+			}
+		case p.check("(") || p.check("{") || p.current().kind == tokenString:
+			kind = kindCall
+			err = p.arguments()
+		default:
+			return kind, nil
+		}
+
+		if err != nil {
+			return kindOther, err
+		}
+	}
+}
+
+// arguments parses the arguments of a function call.
+func (p *parser) arguments() error {
+	line := p.current().line
+
+	switch {
+	case p.current().kind == tokenString:
+		p.advance()
+		return nil
+	case p.check("{"):
+		return p.table()
+	case p.accept("("):
+		if !p.check(")") {
+			if err := p.expressionList(); err != nil {
+				return err
+			}
+		}
+
+		return p.expectMatch(")", "(", line)
+	}
+
+	return p.errorNear("function arguments expected")
+}
+
+// table parses a table constructor.
+func (p *parser) table() error {
+	line := p.advance().line
+
+	for !p.check("}") {
+		switch {
+		case p.check("["):
+			fieldLine := p.advance().line
+
+			if err := p.expression(); err != nil {
+				return err
+			}
+
+			if err := p.expectMatch("]", "[", fieldLine); err != nil {
+				return err
+			}
+
+			if err := p.expect("="); err != nil {
+				return err
+			}
+		case p.current().kind == tokenName && p.peek().kind == tokenSymbol && p.peek().value == "=":
+			p.advance()
+			p.advance()
+		}
+
+		if err := p.expression(); err != nil {
+			return err
+		}
+
+		if !p.accept(",") && !p.accept(";") {
+			break
+		}
+	}
+
+	return p.expectMatch("}", "{", line)
+}
@@ -0,0 +1,205 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package pattern
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const (
+	// RegexPrefix marks a pattern as a regular expression.
+	RegexPrefix = "re:"
+	// GlobPrefix marks a pattern as a glob, without it wildcards are plain characters of a name.
+	GlobPrefix = "glob:"
+	// GlobStar matches any number of segments, including none.
+	GlobStar = "**"
+)
+
+var ErrInvalidPattern = errors.New("invalid pattern")
+
+type Kind int
+
+const (
+	Plain Kind = iota
+	Glob
+	Regex
+)
+
+var _cache sync.Map //nolint:gochecknoglobals // reason: rules are matched against every file of every archive.
+
+// Pattern matches a run of segments of a slash separated path.
+// Plain patterns match equal segments, globs prefixed with GlobPrefix match path.Match wildcards per segment and
+// GlobStar for any number of segments, regular expressions prefixed with RegexPrefix match the joined path and may be
+// anchored with ^ and $.
+type Pattern struct {
+	Raw  string
+	Kind Kind
+
+	segments []string
+	regex    *regexp.Regexp
+}
+
+// IsPlain returns true if raw is neither a glob nor a regular expression. Plain names may contain wildcard
+// characters, such as 'Mod [HD]', which match themselves.
+func IsPlain(raw string) bool {
+	return !strings.HasPrefix(raw, RegexPrefix) && !strings.HasPrefix(raw, GlobPrefix)
+}
+
+// Compile parses raw, compiled patterns are cached.
+func Compile(raw string) (Pattern, error) {
+	if cached, ok := _cache.Load(raw); ok {
+		return cached.(Pattern), nil //nolint:forcetypeassert // reason: only patterns are stored.
+	}
+
+	compiled := Pattern{Raw: raw, Kind: Plain, segments: nil, regex: nil}
+
+	switch {
+	case strings.HasPrefix(raw, RegexPrefix):
+		regex, err := regexp.Compile(strings.TrimPrefix(raw, RegexPrefix))
+		if err != nil {
+			return compiled, fmt.Errorf("%w %q: %w", ErrInvalidPattern, raw, err)
+		}
+
+		compiled.Kind, compiled.regex = Regex, regex
+	case strings.HasPrefix(raw, GlobPrefix):
+		compiled.Kind = Glob
+		compiled.segments = segments(strings.TrimPrefix(raw, GlobPrefix))
+
+		for _, segment := range compiled.segments {
+			if _, err := path.Match(segment, ""); err != nil {
+				return compiled, fmt.Errorf("%w %q: %w", ErrInvalidPattern, raw, err)
+			}
+		}
+
+		// The search is not anchored, so leading GlobStar segments only move the match to the start of the path.
+		for len(compiled.segments) > 1 && compiled.segments[0] == GlobStar {
+			compiled.segments = compiled.segments[1:]
+		}
+	default:
+		compiled.segments = segments(raw)
+	}
+
+	_cache.Store(raw, compiled)
+
+	return compiled, nil
+}
+
+// Match returns true if the pattern matches a run of parts.
+func (p Pattern) Match(parts []string) bool {
+	start, _ := p.Find(parts)
+	return start >= 0
+}
+
+// Find returns the index and the number of segments of the first match in parts, or -1 if there is none.
+func (p Pattern) Find(parts []string) (int, int) {
+	if p.Kind == Regex {
+		return p.findRegex(parts)
+	}
+
+	if len(p.segments) == 0 {
+		return -1, 0
+	}
+
+	for start := range len(parts) + 1 {
+		if p.Kind == Plain {
+			if start+len(p.segments) <= len(parts) && slices.Equal(parts[start:start+len(p.segments)], p.segments) {
+				return start, len(p.segments)
+			}
+
+			continue
+		}
+
+		if length := matchGlob(p.segments, parts[start:]); length >= 0 {
+			return start, length
+		}
+	}
+
+	return -1, 0
+}
+
+// Replace replaces the first match in parts by the segments of replacement.
+// Regular expressions replace every match and expand capture groups like $1 and ${name} in replacement.
+func (p Pattern) Replace(parts []string, replacement string) []string {
+	if p.Kind == Regex {
+		return segments(p.regex.ReplaceAllString(strings.Join(parts, "/"), replacement))
+	}
+
+	start, length := p.Find(parts)
+	if start < 0 {
+		return parts
+	}
+
+	return slices.Concat(parts[:start], segments(replacement), parts[start+length:])
+}
+
+func (p Pattern) findRegex(parts []string) (int, int) {
+	joined := strings.Join(parts, "/")
+
+	location := p.regex.FindStringIndex(joined)
+	if location == nil {
+		return -1, 0
+	}
+
+	start := strings.Count(joined[:location[0]], "/")
+	end := strings.Count(strings.TrimSuffix(joined[:location[1]], "/"), "/") + 1
+
+	return start, max(end-start, 0)
+}
+
+// matchGlob returns the number of parts matched by segments from the start of parts, or -1.
+// GlobStar is greedy, so the longest match is returned.
+func matchGlob(segments, parts []string) int {
+	if len(segments) == 0 {
+		return 0
+	}
+
+	if segments[0] == GlobStar {
+		for count := len(parts); count >= 0; count-- {
+			if rest := matchGlob(segments[1:], parts[count:]); rest >= 0 {
+				return count + rest
+			}
+		}
+
+		return -1
+	}
+
+	if len(parts) == 0 {
+		return -1
+	}
+
+	if ok, _ := path.Match(segments[0], parts[0]); !ok {
+		return -1
+	}
+
+	if rest := matchGlob(segments[1:], parts[1:]); rest >= 0 {
+		return rest + 1
+	}
+
+	return -1
+}
+
+func segments(str string) []string {
+	return slices.DeleteFunc(strings.Split(strings.ReplaceAll(str, "\\", "/"), "/"), func(segment string) bool { return segment == "" })
+}
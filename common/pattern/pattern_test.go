@@ -0,0 +1,87 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package pattern_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hkmh223/pd2mm/common/pattern"
+)
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		pattern string
+		path    string
+		start   int
+		length  int
+	}{
+		{pattern: "hooks", path: "Mod/hooks/menu.lua", start: 1, length: 1},
+		{pattern: "glob:hooks/**/*.lua", path: "Mod/hooks/lib/menu.lua", start: 1, length: 3},
+		{pattern: "glob:hooks/**/*.lua", path: "Mod/hooks/menu.lua", start: 1, length: 2},
+		{pattern: "glob:**/mod.txt", path: "Mod/mod.txt", start: 1, length: 1},
+		{pattern: "glob:*_HD", path: "Pack/Weapons_HD/units", start: 1, length: 1},
+		{pattern: "glob:*_HD", path: "Pack/Weapons/units", start: -1, length: 0},
+		{pattern: "Mod [HD]", path: "Pack/Mod [HD]/units", start: 1, length: 1},
+		{pattern: "Mod*", path: "Pack/Mod [HD]/units", start: -1, length: 0},
+		{pattern: `re:^Mod/.*\.lua$`, path: "Mod/hooks/menu.lua", start: 0, length: 3},
+		{pattern: `re:^hooks`, path: "Mod/hooks/menu.lua", start: -1, length: 0},
+		{pattern: `re:[^/]+_HD`, path: "Pack/Weapons_HD/units", start: 1, length: 1},
+	}
+
+	for _, c := range cases {
+		compiled, err := pattern.Compile(c.pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if start, length := compiled.Find(strings.Split(c.path, "/")); start != c.start || length != c.length {
+			t.Errorf("%s in %s: got %d %d, want %d %d", c.pattern, c.path, start, length, c.start, c.length)
+		}
+	}
+}
+
+func TestReplace(t *testing.T) {
+	t.Parallel()
+
+	cases := map[[3]string]string{
+		{"Weapons_HD", "Weapons_HD", "Weapons"}:                "Weapons/units",
+		{"Weapons_HD", "glob:*_HD", "Weapons"}:                 "Weapons/units",
+		{"Weapons_HD", `re:^([^/]+)_HD`, "$1"}:                 "Weapons/units",
+		{"Weapons_HD", `re:^(?P<name>[^/]+)_HD`, "HD/${name}"}: "HD/Weapons/units",
+	}
+
+	for c, expected := range cases {
+		compiled, err := pattern.Compile(c[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if result := compiled.Replace([]string{c[0], "units"}, c[2]); !slices.Equal(result, strings.Split(expected, "/")) {
+			t.Errorf("%s: got %v, want %s", c[1], result, expected)
+		}
+	}
+
+	if _, err := pattern.Compile("re:("); err == nil {
+		t.Error("expected an invalid regular expression to fail")
+	}
+}
@@ -19,6 +19,9 @@
 package process
 
 import (
+	"bytes"
+	"errors"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -36,12 +39,56 @@ func Exists(name string) bool {
 
 // Run a file with the given name.
 func RunProcess(name string, hide, rel, redirect bool, arg ...string) error {
+	cmd, err := command(name, hide, rel, arg...)
+	if err != nil {
+		return err
+	}
+
+	if redirect {
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+	}
+
+	if err := cmd.Run(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Run a file with the given name, writing its stdout to stdout. The exit code and everything written to stderr are returned,
+// the exit code is -1 if the process could not be started.
+func RunProcessCapture(name string, hide, rel bool, stdout io.Writer, arg ...string) (int, string, error) {
+	cmd, err := command(name, hide, rel, arg...)
+	if err != nil {
+		return -1, "", err
+	}
+
+	var stderr bytes.Buffer
+
+	cmd.Stdout = stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.ExitCode(), stderr.String(), err
+		}
+
+		return -1, stderr.String(), err
+	}
+
+	return 0, stderr.String(), nil
+}
+
+// command creates the command for a file with the given name, relative to the executable if rel is set.
+func command(name string, hide, rel bool, arg ...string) (*exec.Cmd, error) {
 	path := name
 
 	if rel {
 		cwd, err := os.Executable()
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		path = filepath.Join(filepath.Dir(cwd), name)
@@ -49,18 +96,9 @@ func RunProcess(name string, hide, rel, redirect bool, arg ...string) error {
 
 	cmd := exec.Command(path, arg...)
 
-	if redirect {
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-	}
-
 	if runtime.GOOS == "windows" {
 		setHideWindowAttr(cmd, hide)
 	}
 
-	if err := cmd.Run(); err != nil {
-		return err
-	}
-
-	return nil
+	return cmd, nil
 }
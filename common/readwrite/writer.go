@@ -37,12 +37,12 @@ type FileEntry struct {
 }
 
 type DataEntry struct {
-	Hash     uint32
+	Hash     uint64
 	FileName string
 }
 
 // FindByHash returns the first entry in data with a matching hash.
-func FindByHash(data []DataEntry, hash uint32) *DataEntry {
+func FindByHash(data []DataEntry, hash uint64) *DataEntry {
 	for _, entry := range data {
 		if entry.Hash == hash {
 			return &entry
@@ -27,6 +27,51 @@ const (
 	ProcessNotFound
 	CouldNotExtract
 	CouldNotCompress
+	CouldNotList
+	Warning
+	FatalError
+	CommandLineError
+	OutOfMemory
+	UserStopped
+	WrongPassword
 )
 
-var ErrSevenZipNotFound = errors.New("7zip was not found")
+// Exit codes documented by 7-Zip.
+const (
+	exitWarning     = 1
+	exitFatal       = 2
+	exitCommandLine = 7
+	exitOutOfMemory = 8
+	exitUserStopped = 255
+)
+
+// wrongPassword is written to stderr by 7-Zip if an encrypted archive can not be opened or a file can not be decrypted.
+const wrongPassword = "Wrong password"
+
+var (
+	ErrSevenZipNotFound = errors.New("7zip was not found")
+	ErrWarning          = errors.New("7zip finished with warnings, some files may be missing")
+	ErrFatal            = errors.New("7zip stopped because of a fatal error, the archive may be damaged or unsupported")
+	ErrCommandLine      = errors.New("7zip was given an invalid command line")
+	ErrOutOfMemory      = errors.New("7zip ran out of memory")
+	ErrUserStopped      = errors.New("7zip was stopped by the user")
+	ErrWrongPassword    = errors.New("7zip could not decrypt the archive, the password is missing or wrong")
+)
+
+// exitError returns the ErrorCode and error of a 7-Zip exit code, fallback is used for codes 7-Zip does not document.
+func exitError(code int, fallback ErrorCode) (ErrorCode, error) {
+	switch code {
+	case exitWarning:
+		return Warning, ErrWarning
+	case exitFatal:
+		return FatalError, ErrFatal
+	case exitCommandLine:
+		return CommandLineError, ErrCommandLine
+	case exitOutOfMemory:
+		return OutOfMemory, ErrOutOfMemory
+	case exitUserStopped:
+		return UserStopped, ErrUserStopped
+	}
+
+	return fallback, nil
+}
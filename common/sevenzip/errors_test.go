@@ -0,0 +1,91 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package sevenzip_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hkmh223/pd2mm/common/sevenzip"
+)
+
+func TestExitError(t *testing.T) {
+	t.Parallel()
+
+	tests := map[int]struct {
+		code sevenzip.ErrorCode
+		err  error
+	}{
+		1:   {code: sevenzip.Warning, err: sevenzip.ErrWarning},
+		2:   {code: sevenzip.FatalError, err: sevenzip.ErrFatal},
+		7:   {code: sevenzip.CommandLineError, err: sevenzip.ErrCommandLine},
+		8:   {code: sevenzip.OutOfMemory, err: sevenzip.ErrOutOfMemory},
+		255: {code: sevenzip.UserStopped, err: sevenzip.ErrUserStopped},
+		3:   {code: sevenzip.CouldNotExtract, err: nil},
+		-1:  {code: sevenzip.CouldNotExtract, err: nil},
+	}
+
+	for exit, expected := range tests {
+		code, err := sevenzip.ExitError(exit, sevenzip.CouldNotExtract)
+		if code != expected.code || !errors.Is(err, expected.err) {
+			t.Errorf("exit code %d: expected %d %v, got %d %v", exit, expected.code, expected.err, code, err)
+		}
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		body string
+		code sevenzip.ErrorCode
+		err  error
+	}{
+		"success": {body: "exit 0", code: sevenzip.NoError, err: nil},
+		"warning": {body: "echo 'WARNING: a.txt' >&2; exit 1", code: sevenzip.Warning, err: sevenzip.ErrWarning},
+		"fatal":   {body: "exit 2", code: sevenzip.FatalError, err: sevenzip.ErrFatal},
+		"wrong password": {
+			body: "echo 'ERROR: Wrong password : a.txt' >&2; exit 2",
+			code: sevenzip.WrongPassword,
+			err:  sevenzip.ErrWrongPassword,
+		},
+		"undocumented": {body: "echo 'broken' >&2; exit 3", code: sevenzip.CouldNotExtract, err: nil},
+	}
+
+	for name, test := range tests {
+		bin, _ := fakeSevenZip(t, test.body)
+
+		code, err := sevenzip.ExtractWithBin("mod.7z", "out", bin, false, options())
+		if code != test.code {
+			t.Errorf("%s: expected code %d, got %d %v", name, test.code, code, err)
+		}
+
+		if test.err != nil && !errors.Is(err, test.err) {
+			t.Errorf("%s: expected %v, got %v", name, test.err, err)
+		}
+
+		if name == "success" && err != nil {
+			t.Errorf("%s: expected no error, got %v", name, err)
+		}
+
+		if name == "undocumented" && err == nil {
+			t.Errorf("%s: expected the exit error to be kept", name)
+		}
+	}
+}
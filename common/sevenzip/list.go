@@ -0,0 +1,242 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package sevenzip
+
+import (
+	"bufio"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hkmh223/pd2mm/common/filesystem"
+	"github.com/hkmh223/pd2mm/common/process"
+)
+
+const (
+	listSeparator = "----------"
+	modifiedTime  = "2006-01-02 15:04:05.999999999"
+)
+
+// Entry is a file or folder inside an archive as listed by `7z l -slt`.
+type Entry struct {
+	Path       string
+	Size       uint64
+	PackedSize uint64
+	CRC        uint32
+	Modified   time.Time
+	Attributes string
+	Encrypted  bool
+	Folder     bool
+	Link       bool
+}
+
+// List lists the contents of an archive.
+func List(src string, opts ...ExtractionOptions) ([]Entry, ErrorCode, error) {
+	opt := assureExtractionOptions(opts...)
+
+	if !process.Exists(Name) {
+		return nil, ProcessNotFound, ErrSevenZipNotFound
+	}
+
+	return list(Name, src, opt)
+}
+
+// ListWithBin lists the contents of an archive using a custom binary.
+func ListWithBin(src, bin string, opts ...ExtractionOptions) ([]Entry, ErrorCode, error) {
+	opt := assureExtractionOptions(opts...)
+
+	if !filesystem.Exists(bin) {
+		return nil, ProcessNotFound, ErrSevenZipNotFound
+	}
+
+	return list(bin, src, opt)
+}
+
+// ParseList parses the technical listing written by `7z l -slt`. The archive itself is not an entry.
+func ParseList(output string) ([]Entry, error) {
+	var (
+		entries []Entry
+		fields  map[string]string
+		started bool
+	)
+
+	flush := func() error {
+		if len(fields) == 0 {
+			return nil
+		}
+
+		entry, err := parseEntry(fields)
+		if err != nil {
+			return err
+		}
+
+		entries = append(entries, entry)
+		fields = nil
+
+		return nil
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+
+		if !started {
+			started = line == listSeparator
+			continue
+		}
+
+		if line == "" {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, " = ")
+		if !ok {
+			key, value = strings.TrimSuffix(line, " ="), ""
+		}
+
+		if fields == nil {
+			fields = map[string]string{}
+		}
+
+		fields[key] = value
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// EntryPaths returns the path of every entry, for passing entries to ExtractFiles or ExtractExcluding.
+func EntryPaths(entries []Entry) []string {
+	paths := make([]string, 0, len(entries))
+
+	for _, entry := range entries {
+		paths = append(paths, entry.Path)
+	}
+
+	return paths
+}
+
+// TotalSize returns the uncompressed size of every entry.
+func TotalSize(entries []Entry) uint64 {
+	var size uint64
+
+	for _, entry := range entries {
+		size += entry.Size
+	}
+
+	return size
+}
+
+// Encrypted returns true if any entry is encrypted.
+func Encrypted(entries []Entry) bool {
+	return slices.ContainsFunc(entries, func(entry Entry) bool { return entry.Encrypted })
+}
+
+func list(bin, src string, opt ExtractionOptions) ([]Entry, ErrorCode, error) {
+	var output strings.Builder
+
+	listOptions := runOptions{hide: opt.HideWindow, relative: opt.Relative, redirect: false, progress: nil, fallback: CouldNotList, stdout: &output}
+
+	code, err := run(bin, listOptions, "l", "-slt", "-p"+opt.Password, "--", src)
+	if err != nil {
+		return nil, code, err
+	}
+
+	entries, err := ParseList(output.String())
+	if err != nil {
+		return nil, CouldNotList, err
+	}
+
+	return entries, NoError, nil
+}
+
+func parseEntry(fields map[string]string) (Entry, error) {
+	entry := Entry{
+		Path:       filesystem.Normalize(fields["Path"]),
+		Size:       0,
+		PackedSize: 0,
+		CRC:        0,
+		Modified:   time.Time{},
+		Attributes: fields["Attributes"],
+		Encrypted:  fields["Encrypted"] == "+",
+		Folder:     fields["Folder"] == "+" || strings.HasPrefix(fields["Attributes"], "D"),
+		Link:       isLink(fields),
+	}
+
+	var err error
+
+	if entry.Size, err = parseUint(fields["Size"], 10, 64); err != nil { //nolint:mnd // reason: decimal.
+		return entry, err
+	}
+
+	if entry.PackedSize, err = parseUint(fields["Packed Size"], 10, 64); err != nil { //nolint:mnd // reason: decimal.
+		return entry, err
+	}
+
+	crc, err := parseUint(fields["CRC"], 16, 32) //nolint:mnd // reason: hexadecimal.
+	if err != nil {
+		return entry, err
+	}
+
+	entry.CRC = uint32(crc)
+
+	if modified := fields["Modified"]; modified != "" {
+		if entry.Modified, err = time.Parse(modifiedTime, modified); err != nil {
+			return entry, err
+		}
+	}
+
+	return entry, nil
+}
+
+// isLink returns true for symbolic and hard links, 7-Zip names the link target field after the archive format
+// and shows the unix mode next to the windows attributes.
+func isLink(fields map[string]string) bool {
+	for _, key := range []string{"Symbolic Link", "Hard Link", "Link"} {
+		if fields[key] != "" {
+			return true
+		}
+	}
+
+	return slices.ContainsFunc(strings.Fields(fields["Attributes"]), func(field string) bool {
+		return len(field) == len("lrwxrwxrwx") && field[0] == 'l'
+	})
+}
+
+// parseUint parses an optional number, 7-Zip leaves values it does not know empty.
+func parseUint(str string, base, bits int) (uint64, error) {
+	if str == "" {
+		return 0, nil
+	}
+
+	return strconv.ParseUint(str, base, bits)
+}
@@ -0,0 +1,109 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package sevenzip_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hkmh223/pd2mm/common/sevenzip"
+)
+
+const listing = `
+7-Zip 23.01 (x64) : Copyright (c) 1999-2023 Igor Pavlov : 2023-06-20
+
+Scanning the drive for archives:
+1 file, 1843 bytes (2 KiB)
+
+Listing archive: MyMod.7z
+
+--
+Path = MyMod.7z
+Type = 7z
+Physical Size = 1843
+Headers Size = 260
+Method = LZMA2:12 7zAES
+Solid = +
+Blocks = 1
+
+----------
+Path = MyMod
+Size = 0
+Packed Size = 0
+Modified = 2024-05-01 12:30:00.1234567
+Attributes = D drwxr-xr-x
+CRC = 
+Encrypted = -
+Method = 
+Block = 
+
+Path = MyMod\mod.txt
+Size = 412
+Packed Size = 1584
+Modified = 2024-05-01 12:30:00
+Attributes = A -rw-r--r--
+CRC = 3610A686
+Encrypted = +
+Method = LZMA2:12 7zAES:19
+Block = 0
+
+Path = MyMod\lua\menu.lua
+Size = 1024
+Packed Size = 
+Modified = 2024-05-01 12:31:00
+Attributes = A -rw-r--r--
+CRC = 0000BEEF
+Encrypted = +
+Method = LZMA2:12 7zAES:19
+Block = 0
+`
+
+func TestParseList(t *testing.T) {
+	t.Parallel()
+
+	entries, err := sevenzip.ParseList(listing)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+
+	folder, file := entries[0], entries[1]
+
+	if !folder.Folder || folder.Path != "MyMod" || folder.Modified.Nanosecond() != 123456700 {
+		t.Errorf("unexpected folder: %+v", folder)
+	}
+
+	modified := time.Date(2024, time.May, 1, 12, 30, 0, 0, time.UTC)
+	if file.Folder || file.Path != "MyMod/mod.txt" || file.Size != 412 || file.PackedSize != 1584 ||
+		file.CRC != 0x3610A686 || !file.Encrypted || !file.Modified.Equal(modified) {
+		t.Errorf("unexpected file: %+v", file)
+	}
+
+	if entries[2].PackedSize != 0 || sevenzip.TotalSize(entries) != 1436 {
+		t.Errorf("unexpected sizes: %+v", entries[2])
+	}
+
+	paths := sevenzip.EntryPaths(entries)
+	if paths[2] != "MyMod/lua/menu.lua" {
+		t.Errorf("unexpected paths: %v", paths)
+	}
+}
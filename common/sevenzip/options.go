@@ -21,12 +21,22 @@ package sevenzip
 type ExtractionOptions struct {
 	HideWindow bool
 	Relative   bool
+
+	// Password is passed to 7-Zip for encrypted archives. It is always passed, even if empty,
+	// so 7-Zip fails with ErrWrongPassword instead of waiting for a password on stdin.
+	Password string
+
+	// Progress is called with the percentage whenever it changes, if it is not nil.
+	Progress func(percent int)
 }
 
 type CompressionOptions struct {
 	HideWindow  bool
 	RedirectStd bool
 
+	// Progress is called with the percentage whenever it changes, if it is not nil.
+	Progress func(percent int)
+
 	FormatFormat   string
 	Level          string
 	Method         string
@@ -42,6 +52,8 @@ func getDefaultExtractionOptions() ExtractionOptions {
 	return ExtractionOptions{
 		HideWindow: true,
 		Relative:   true,
+		Password:   "",
+		Progress:   nil,
 	}
 }
 
@@ -50,6 +62,7 @@ func getDefaultCompressionOptions() CompressionOptions {
 	return CompressionOptions{
 		HideWindow:     true,
 		RedirectStd:    true,
+		Progress:       nil,
 		FormatFormat:   "7z",
 		Level:          "-mx9",
 		Method:         "-m0=lzma2",
@@ -0,0 +1,68 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package sevenzip
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+// progressWriter parses the percentages 7-Zip writes to stdout with `-bsp1`.
+// 7-Zip redraws its progress line with backspaces, so carriage returns, newlines and backspaces all end a line.
+type progressWriter struct {
+	progress func(percent int)
+	line     []byte
+	last     int
+}
+
+func newProgressWriter(progress func(percent int)) *progressWriter {
+	return &progressWriter{progress: progress, line: nil, last: -1}
+}
+
+func (w *progressWriter) Write(p []byte) (int, error) {
+	for _, b := range p {
+		if b != '\b' && b != '\r' && b != '\n' {
+			w.line = append(w.line, b)
+			continue
+		}
+
+		w.parse()
+	}
+
+	return len(p), nil
+}
+
+func (w *progressWriter) parse() {
+	line := bytes.TrimSpace(w.line)
+	w.line = w.line[:0]
+
+	number, _, ok := strings.Cut(string(line), "%")
+	if !ok {
+		return
+	}
+
+	percent, err := strconv.Atoi(number)
+	if err != nil || percent < 0 || percent > 100 || percent == w.last {
+		return
+	}
+
+	w.last = percent
+	w.progress(percent)
+}
@@ -0,0 +1,63 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package sevenzip_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hkmh223/pd2mm/common/sevenzip"
+)
+
+func TestProgressWriter(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		writes   []string
+		expected []int
+	}{
+		"backspaces": {
+			writes:   []string{"  0%\b\b\b\b  4% 1 - mod.txt" + strings.Repeat("\b", 15) + " 57% 2\b\b\b\b\b\b100%\n"},
+			expected: []int{0, 4, 57, 100},
+		},
+		"split number":     {writes: []string{" 1", "2% 1", " - a\r", " 3", "4%", "\r"}, expected: []int{12, 34}},
+		"split line end":   {writes: []string{" 50%", "\b", "\b 75%\r\n"}, expected: []int{50, 75}},
+		"repeated percent": {writes: []string{" 10%\r 10% 2\r 11%\r"}, expected: []int{10, 11}},
+		"no percent":       {writes: []string{"Extracting archive: mod.7z\r\n", "Everything is Ok\n"}, expected: nil},
+		"out of range":     {writes: []string{"101%\r-1%\rabc%\r"}, expected: nil},
+		"unterminated":     {writes: []string{" 20%\r 30%"}, expected: []int{20}},
+	}
+
+	for name, test := range tests {
+		var percents []int
+
+		writer := sevenzip.NewProgressWriter(func(percent int) { percents = append(percents, percent) })
+
+		for _, write := range test.writes {
+			if n, err := writer.Write([]byte(write)); err != nil || n != len(write) {
+				t.Fatalf("%s: wrote %d of %d bytes: %v", name, n, len(write), err)
+			}
+		}
+
+		if !slices.Equal(percents, test.expected) {
+			t.Errorf("%s: expected %v, got %v", name, test.expected, percents)
+		}
+	}
+}
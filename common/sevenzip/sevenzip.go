@@ -19,6 +19,11 @@
 package sevenzip
 
 import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/hkmh223/pd2mm/common/errors"
 	"github.com/hkmh223/pd2mm/common/filesystem"
 	"github.com/hkmh223/pd2mm/common/process"
 )
@@ -29,6 +34,15 @@ const (
 	WindowsName = "7z.exe"
 )
 
+type runOptions struct {
+	hide     bool
+	relative bool
+	redirect bool
+	progress func(percent int)
+	fallback ErrorCode
+	stdout   io.Writer
+}
+
 // Extract extracts the contents of a 7z archive to a directory.
 func Extract(src, dest string, redirect bool, opts ...ExtractionOptions) (ErrorCode, error) {
 	opt := assureExtractionOptions(opts...)
@@ -37,11 +51,7 @@ func Extract(src, dest string, redirect bool, opts ...ExtractionOptions) (ErrorC
 		return ProcessNotFound, ErrSevenZipNotFound
 	}
 
-	if err := process.RunProcess(Name, opt.HideWindow, opt.Relative, redirect, "x", src, "-o"+dest+"/*"); err != nil {
-		return CouldNotExtract, err
-	}
-
-	return NoError, nil
+	return run(Name, extractRunOptions(opt, redirect), "x", "-p"+opt.Password, src, "-o"+dest+"/*")
 }
 
 // ExtractWithBin extracts the contents of a 7z archive to a directory using a custom binary.
@@ -52,11 +62,56 @@ func ExtractWithBin(src, dest, bin string, redirect bool, opts ...ExtractionOpti
 		return ProcessNotFound, ErrSevenZipNotFound
 	}
 
-	if err := process.RunProcess(bin, opt.HideWindow, opt.Relative, redirect, "x", src, "-o"+dest+"/*"); err != nil {
-		return CouldNotCompress, err
+	return run(bin, extractRunOptions(opt, redirect), "x", "-p"+opt.Password, src, "-o"+dest+"/*")
+}
+
+// ExtractFiles extracts only the given paths of a 7z archive to a directory. The paths are passed in a list file, so
+// their number does not matter, and may contain the wildcards * and ?. Entries returned by List can be passed through
+// EntryPaths. Nothing is extracted if files is empty.
+func ExtractFiles(src, dest string, files []string, redirect bool, opts ...ExtractionOptions) (ErrorCode, error) {
+	opt := assureExtractionOptions(opts...)
+
+	if !process.Exists(Name) {
+		return ProcessNotFound, ErrSevenZipNotFound
+	}
+
+	return extractFiles(Name, src, dest, files, redirect, opt)
+}
+
+// ExtractFilesWithBin extracts only the given paths of a 7z archive to a directory using a custom binary.
+func ExtractFilesWithBin(src, dest, bin string, files []string, redirect bool, opts ...ExtractionOptions) (ErrorCode, error) {
+	opt := assureExtractionOptions(opts...)
+
+	if !filesystem.Exists(bin) {
+		return ProcessNotFound, ErrSevenZipNotFound
 	}
 
-	return NoError, nil
+	return extractFiles(bin, src, dest, files, redirect, opt)
+}
+
+// ExtractExcluding extracts the contents of a 7z archive to a directory, except the given paths. The paths are
+// passed in a list file and matched literally, so neither their number nor wildcard characters in them matter.
+// Entries returned by List can be passed through EntryPaths.
+func ExtractExcluding(src, dest string, excluded []string, redirect bool, opts ...ExtractionOptions) (ErrorCode, error) {
+	opt := assureExtractionOptions(opts...)
+
+	if !process.Exists(Name) {
+		return ProcessNotFound, ErrSevenZipNotFound
+	}
+
+	return extractExcluding(Name, src, dest, excluded, redirect, opt)
+}
+
+// ExtractExcludingWithBin extracts the contents of a 7z archive to a directory, except the given paths, using a
+// custom binary.
+func ExtractExcludingWithBin(src, dest, bin string, excluded []string, redirect bool, opts ...ExtractionOptions) (ErrorCode, error) {
+	opt := assureExtractionOptions(opts...)
+
+	if !filesystem.Exists(bin) {
+		return ProcessNotFound, ErrSevenZipNotFound
+	}
+
+	return extractExcluding(bin, src, dest, excluded, redirect, opt)
 }
 
 // Compress compresses a directory to a 7z archive.
@@ -67,12 +122,7 @@ func Compress(src, dest string, redirect bool, opts ...CompressionOptions) (Erro
 		return ProcessNotFound, ErrSevenZipNotFound
 	}
 
-	//nolint:lll // reason: calling RunProcess means that we can't pass CompressOptions directly.
-	if err := process.RunProcess(Name, true, false, redirect, "a", "-t"+opt.FormatFormat, dest, src+"/*", opt.Level, opt.Method, opt.DictionarySize, opt.FastBytes, opt.SolidBlockSize, opt.Multithreading, opt.Memory); err != nil {
-		return CouldNotCompress, err
-	}
-
-	return NoError, nil
+	return run(Name, compressRunOptions(opt, false, redirect), compressArgs(src, dest, opt)...)
 }
 
 // CompressWithBin compresses a directory to a 7z archive.
@@ -83,10 +133,110 @@ func CompressWithBin(src, dest, bin string, redirectStd bool, opts ...Compressio
 		return ProcessNotFound, ErrSevenZipNotFound
 	}
 
-	//nolint:lll // reason: calling RunProcess means that we can't pass CompressOptions directly.
-	if err := process.RunProcess(bin, true, true, redirectStd, "a", "-t"+opt.FormatFormat, dest, src+"/*", opt.Level, opt.Method, opt.DictionarySize, opt.FastBytes, opt.SolidBlockSize, opt.Multithreading, opt.Memory); err != nil {
-		return CouldNotCompress, err
+	return run(bin, compressRunOptions(opt, true, redirectStd), compressArgs(src, dest, opt)...)
+}
+
+func extractFiles(bin, src, dest string, files []string, redirect bool, opt ExtractionOptions) (ErrorCode, error) {
+	if len(files) == 0 {
+		return NoError, nil
 	}
 
-	return NoError, nil
+	list, err := writeList(files)
+	if err != nil {
+		return CouldNotExtract, err
+	}
+	defer os.Remove(list)
+
+	return run(bin, extractRunOptions(opt, redirect), "x", "-p"+opt.Password, src, "-o"+dest+"/*", "-scsUTF-8", "-i@"+list)
+}
+
+func extractExcluding(bin, src, dest string, excluded []string, redirect bool, opt ExtractionOptions) (ErrorCode, error) {
+	args := []string{"x", "-p" + opt.Password, src, "-o" + dest + "/*"}
+
+	if len(excluded) > 0 {
+		list, err := writeList(excluded)
+		if err != nil {
+			return CouldNotExtract, err
+		}
+		defer os.Remove(list)
+
+		args = append(args, "-spd", "-scsUTF-8", "-x@"+list)
+	}
+
+	return run(bin, extractRunOptions(opt, redirect), args...)
+}
+
+// writeList writes paths to a temporary list file, one per line, and returns its name.
+func writeList(paths []string) (string, error) {
+	file, err := os.CreateTemp("", "pd2mm-7z-*.txt")
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := file.WriteString(strings.Join(paths, "\n") + "\n"); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+
+		return "", err
+	}
+
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+
+	return file.Name(), nil
+}
+
+// run runs 7-Zip with progress written to stdout. The exit code is mapped to an ErrorCode,
+// and the error carries what 7-Zip wrote to stderr.
+func run(bin string, opt runOptions, args ...string) (ErrorCode, error) {
+	stdout := opt.stdout
+	if stdout == nil {
+		stdout = io.Discard
+	}
+
+	if opt.progress != nil {
+		stdout = io.MultiWriter(stdout, newProgressWriter(opt.progress))
+		args = append(args[:1:1], append([]string{"-bsp1"}, args[1:]...)...)
+	}
+
+	if opt.redirect {
+		stdout = io.MultiWriter(os.Stdout, stdout)
+	}
+
+	code, stderr, err := process.RunProcessCapture(bin, opt.hide, opt.relative, stdout, args...)
+	if err == nil {
+		return NoError, nil
+	}
+
+	errorCode, exitErr := exitError(code, opt.fallback)
+	if strings.Contains(stderr, wrongPassword) {
+		errorCode, exitErr = WrongPassword, ErrWrongPassword
+	}
+
+	if exitErr == nil {
+		exitErr = err
+	}
+
+	if message := strings.TrimSpace(stderr); message != "" {
+		return errorCode, &errors.MError{Header: "7zip", Message: message, Err: exitErr}
+	}
+
+	return errorCode, exitErr
+}
+
+func extractRunOptions(opt ExtractionOptions, redirect bool) runOptions {
+	return runOptions{hide: opt.HideWindow, relative: opt.Relative, redirect: redirect, progress: opt.Progress, fallback: CouldNotExtract, stdout: nil}
+}
+
+func compressRunOptions(opt CompressionOptions, relative, redirect bool) runOptions {
+	return runOptions{hide: opt.HideWindow, relative: relative, redirect: redirect, progress: opt.Progress, fallback: CouldNotCompress, stdout: nil}
+}
+
+func compressArgs(src, dest string, opt CompressionOptions) []string {
+	return []string{
+		"a", "-t" + opt.FormatFormat, dest, src + "/*",
+		opt.Level, opt.Method, opt.DictionarySize, opt.FastBytes, opt.SolidBlockSize, opt.Multithreading, opt.Memory,
+	}
 }
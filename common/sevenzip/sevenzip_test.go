@@ -0,0 +1,129 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package sevenzip_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hkmh223/pd2mm/common/sevenzip"
+)
+
+// fakeSevenZip writes a shell script standing in for 7-Zip and returns its path and the path of its log. The script
+// writes every argument to the log, followed by the lines of the list files passed with -i@ or -x@, then runs body.
+func fakeSevenZip(t *testing.T, body string) (string, string) {
+	t.Helper()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake 7-Zip is a shell script")
+	}
+
+	dir := t.TempDir()
+	bin := filepath.Join(dir, "7z")
+	log := filepath.Join(dir, "args.txt")
+
+	script := "#!/bin/sh\n" +
+		": > '" + log + "'\n" +
+		"for arg in \"$@\"; do\n" +
+		"\tprintf '%s\\n' \"$arg\" >> '" + log + "'\n" +
+		"\tcase \"$arg\" in -i@*|-x@*) cat \"${arg#-?@}\" >> '" + log + "';; esac\n" +
+		"done\n" + body + "\n"
+
+	if err := os.WriteFile(bin, []byte(script), 0o700); err != nil { //nolint:gosec // reason: the script is executed.
+		t.Fatal(err)
+	}
+
+	return bin, log
+}
+
+// fakeArgs returns the lines written by the fake 7-Zip.
+func fakeArgs(t *testing.T, log string) []string {
+	t.Helper()
+
+	content, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+}
+
+func options() sevenzip.ExtractionOptions {
+	return sevenzip.ExtractionOptions{HideWindow: true, Relative: false, Password: "secret", Progress: nil}
+}
+
+func TestExtractFilesWithBin(t *testing.T) {
+	t.Parallel()
+
+	bin, log := fakeSevenZip(t, "exit 0")
+	files := []string{"MyMod/mod.txt", "MyMod/lua/*.lua", "MyMod/50% [HD].png"}
+
+	if _, err := sevenzip.ExtractFilesWithBin("mod.7z", "out", bin, files, false, options()); err != nil {
+		t.Fatal(err)
+	}
+
+	args := fakeArgs(t, log)
+
+	if !slices.Equal(args[:5], []string{"x", "-psecret", "mod.7z", "-oout/*", "-scsUTF-8"}) || !strings.HasPrefix(args[5], "-i@") {
+		t.Fatalf("unexpected arguments %q", args)
+	}
+
+	if !slices.Equal(args[6:], files) {
+		t.Fatalf("expected the list file to hold %q, got %q", files, args[6:])
+	}
+}
+
+func TestExtractFilesEmpty(t *testing.T) {
+	t.Parallel()
+
+	bin, log := fakeSevenZip(t, "exit 2")
+
+	if code, err := sevenzip.ExtractFilesWithBin("mod.7z", "out", bin, nil, false, options()); code != sevenzip.NoError || err != nil {
+		t.Fatalf("expected nothing to be extracted, got %d %v", code, err)
+	}
+
+	if _, err := os.Stat(log); !os.IsNotExist(err) {
+		t.Fatal("expected 7-Zip not to run")
+	}
+}
+
+func TestExtractExcludingWithBin(t *testing.T) {
+	t.Parallel()
+
+	bin, log := fakeSevenZip(t, "exit 0")
+	links := []string{"MyMod/link*", "MyMod/other"}
+
+	if _, err := sevenzip.ExtractExcludingWithBin("mod.7z", "out", bin, links, false, options()); err != nil {
+		t.Fatal(err)
+	}
+
+	args := fakeArgs(t, log)
+
+	if !slices.Equal(args[:6], []string{"x", "-psecret", "mod.7z", "-oout/*", "-spd", "-scsUTF-8"}) || !strings.HasPrefix(args[6], "-x@") {
+		t.Fatalf("unexpected arguments %q", args)
+	}
+
+	if !slices.Equal(args[7:], links) {
+		t.Fatalf("expected the list file to hold %q, got %q", links, args[7:])
+	}
+}
@@ -0,0 +1,159 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package tar
+
+import (
+	tarfile "archive/tar"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hkmh223/pd2mm/common/archive"
+	"github.com/hkmh223/pd2mm/common/xz"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported tar format")
+
+// Extensions returns every extension Untar accepts, compound extensions first.
+func Extensions() []string {
+	return []string{".tar.gz", ".tar.xz", ".tar.bz2", ".tgz", ".txz", ".tbz2", ".tbz", ".tar"}
+}
+
+// TrimExtension returns name without the tar extension, or false if name is not a tar archive.
+func TrimExtension(name string) (string, bool) {
+	for _, ext := range Extensions() {
+		if len(name) > len(ext) && strings.EqualFold(name[len(name)-len(ext):], ext) {
+			return name[:len(name)-len(ext)], true
+		}
+	}
+
+	return name, false
+}
+
+// Untar extracts the tar archive at src into dest. Archives compressed with gzip, xz or bzip2 are decompressed
+// based on their signature.
+func Untar(src, dest string) error {
+	guard, err := archive.NewGuard(src, dest, archive.Limits{MaxSize: 0, MaxRatio: 0, MaxEntries: 0})
+	if err != nil {
+		return err
+	}
+
+	return UntarWithGuard(src, guard)
+}
+
+// UntarWithGuard extracts the tar archive at src into the destination of guard.
+func UntarWithGuard(src string, guard *archive.Guard) error {
+	format, err := archive.DetectFile(src)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader, err := decompressor(format, file)
+	if err != nil {
+		return err
+	}
+
+	return Extract(reader, guard)
+}
+
+// Extract extracts the uncompressed tar stream r into the destination of guard. Only directories and regular files
+// are extracted, links are skipped.
+func Extract(r io.Reader, guard *archive.Guard) error {
+	archive := tarfile.NewReader(r)
+
+	for {
+		header, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		path, err := guard.Path(header.Name)
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tarfile.TypeSymlink, tarfile.TypeLink:
+			guard.Skip(header.Name)
+		case tarfile.TypeDir:
+			if err := guard.Entry(header.Name, 0); err != nil {
+				return err
+			}
+
+			if err := os.MkdirAll(path, os.ModePerm); err != nil {
+				return err
+			}
+		case tarfile.TypeReg:
+			if err := guard.Entry(header.Name, uint64(max(header.Size, 0))); err != nil {
+				return err
+			}
+
+			if err := writeFile(header.Name, path, archive, guard); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func decompressor(format archive.Format, r io.Reader) (io.Reader, error) {
+	switch format { //nolint:exhaustive // reason: other formats are not tar archives.
+	case archive.TarGzip:
+		return gzip.NewReader(r)
+	case archive.TarXz:
+		return xz.NewReader(r)
+	case archive.TarBzip2:
+		return bzip2.NewReader(r), nil
+	case archive.Tar:
+		return r, nil
+	}
+
+	return nil, ErrUnsupportedFormat
+}
+
+func writeFile(name, path string, r io.Reader, guard *archive.Guard) error {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err := guard.Copy(name, file, r); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,31 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package xz
+
+import "errors"
+
+var (
+	ErrInvalidHeader     = errors.New("xz: invalid stream header")
+	ErrInvalidFooter     = errors.New("xz: invalid stream footer")
+	ErrInvalidIndex      = errors.New("xz: invalid index")
+	ErrInvalidBlock      = errors.New("xz: invalid block header")
+	ErrUnsupportedFilter = errors.New("xz: unsupported filter, only LZMA2 is supported")
+	ErrChecksum          = errors.New("xz: checksum mismatch")
+	ErrCorrupt           = errors.New("xz: corrupt data")
+)
@@ -0,0 +1,398 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package xz
+
+const (
+	probBits      = 11
+	probInit      = 1 << (probBits - 1)
+	probMoveBits  = 5
+	rangeTopValue = 1 << 24
+
+	lzmaStates      = 12
+	lzmaPosBitsMax  = 4
+	lzmaLenStates   = 4
+	lzmaEndPosModel = 14
+	lzmaFullDist    = 128
+	lzmaAlignBits   = 4
+	lzmaMatchMinLen = 2
+	lzmaLiteralSize = 0x300
+	lzmaLiteralMax  = 4
+)
+
+type prob uint16
+
+// rangeDecoder decodes the range coded data of a single LZMA2 chunk.
+type rangeDecoder struct {
+	data  []byte
+	pos   int
+	rng   uint32
+	code  uint32
+	extra bool
+}
+
+func (rc *rangeDecoder) init(data []byte) error {
+	const initBytes = 5
+
+	if len(data) < initBytes || data[0] != 0 {
+		return ErrCorrupt
+	}
+
+	rc.data = data
+	rc.pos = initBytes
+	rc.rng = 0xFFFFFFFF
+	rc.code = uint32(data[1])<<24 | uint32(data[2])<<16 | uint32(data[3])<<8 | uint32(data[4])
+	rc.extra = false
+
+	return nil
+}
+
+// normalize shifts in the next byte when the range gets too small. Reading past the chunk marks the decoder as corrupt.
+func (rc *rangeDecoder) normalize() {
+	if rc.rng >= rangeTopValue {
+		return
+	}
+
+	rc.rng <<= 8
+	rc.code <<= 8
+
+	if rc.pos < len(rc.data) {
+		rc.code |= uint32(rc.data[rc.pos])
+		rc.pos++
+	} else {
+		rc.extra = true
+	}
+}
+
+// finished returns true if every byte of the chunk was used and the encoder flushed a zero code.
+func (rc *rangeDecoder) finished() bool {
+	rc.normalize()
+
+	return rc.pos == len(rc.data) && rc.code == 0 && !rc.extra
+}
+
+func (rc *rangeDecoder) bit(p *prob) uint32 {
+	rc.normalize()
+
+	bound := (rc.rng >> probBits) * uint32(*p)
+	if rc.code < bound {
+		rc.rng = bound
+		*p += (1<<probBits - *p) >> probMoveBits
+
+		return 0
+	}
+
+	rc.rng -= bound
+	rc.code -= bound
+	*p -= *p >> probMoveBits
+
+	return 1
+}
+
+func (rc *rangeDecoder) bitTree(probs []prob, bits uint) uint32 {
+	m := uint32(1)
+	for range bits {
+		m = m<<1 | rc.bit(&probs[m])
+	}
+
+	return m - 1<<bits
+}
+
+func (rc *rangeDecoder) reverseBitTree(probs []prob, bits uint) uint32 {
+	m, symbol := uint32(1), uint32(0)
+	for i := range bits {
+		bit := rc.bit(&probs[m])
+		m = m<<1 | bit
+		symbol |= bit << i
+	}
+
+	return symbol
+}
+
+func (rc *rangeDecoder) direct(bits uint) uint32 {
+	var result uint32
+
+	for range bits {
+		rc.normalize()
+		rc.rng >>= 1
+		rc.code -= rc.rng
+		mask := 0 - (rc.code >> 31)
+		rc.code += rc.rng & mask
+		result = result<<1 + mask + 1
+	}
+
+	return result
+}
+
+type lengthDecoder struct {
+	choice  prob
+	choice2 prob
+	low     [1 << lzmaPosBitsMax][1 << 3]prob
+	mid     [1 << lzmaPosBitsMax][1 << 3]prob
+	high    [1 << 8]prob
+}
+
+func (l *lengthDecoder) reset() {
+	l.choice, l.choice2 = probInit, probInit
+	resetProbs(l.high[:])
+
+	for i := range l.low {
+		resetProbs(l.low[i][:])
+		resetProbs(l.mid[i][:])
+	}
+}
+
+// decode returns the length of a match minus the minimum match length.
+func (l *lengthDecoder) decode(rc *rangeDecoder, posState uint32) uint32 {
+	if rc.bit(&l.choice) == 0 {
+		return rc.bitTree(l.low[posState][:], 3) //nolint:mnd // reason: low lengths use 3 bits.
+	}
+
+	if rc.bit(&l.choice2) == 0 {
+		return 8 + rc.bitTree(l.mid[posState][:], 3) //nolint:mnd // reason: mid lengths start at 8 and use 3 bits.
+	}
+
+	return 16 + rc.bitTree(l.high[:], 8) //nolint:mnd // reason: high lengths start at 16 and use 8 bits.
+}
+
+// lzmaDecoder holds the LZMA state that LZMA2 chunks may carry over to the next chunk.
+type lzmaDecoder struct {
+	lc, lp, pb uint
+
+	literal    []prob
+	isMatch    [lzmaStates << lzmaPosBitsMax]prob
+	isRep      [lzmaStates]prob
+	isRepG0    [lzmaStates]prob
+	isRepG1    [lzmaStates]prob
+	isRepG2    [lzmaStates]prob
+	isRep0Long [lzmaStates << lzmaPosBitsMax]prob
+	posSlot    [lzmaLenStates][1 << 6]prob
+	posSpecial [1 + lzmaFullDist - lzmaEndPosModel]prob
+	align      [1 << lzmaAlignBits]prob
+	length     lengthDecoder
+	repLength  lengthDecoder
+
+	state uint32
+	rep   [4]uint32
+}
+
+// setProperties sets lc, lp and pb from the properties byte of an LZMA2 chunk.
+func (d *lzmaDecoder) setProperties(props byte) error {
+	if props >= (4*5+4)*9+9 { //nolint:mnd // reason: pb <= 4, lp <= 4, lc <= 8.
+		return ErrCorrupt
+	}
+
+	d.lc = uint(props % 9)     //nolint:mnd // reason: properties encoding.
+	d.lp = uint(props / 9 % 5) //nolint:mnd // reason: properties encoding.
+	d.pb = uint(props / 9 / 5) //nolint:mnd // reason: properties encoding.
+
+	if d.lc+d.lp > lzmaLiteralMax {
+		return ErrCorrupt
+	}
+
+	if size := lzmaLiteralSize << (d.lc + d.lp); cap(d.literal) >= size {
+		d.literal = d.literal[:size]
+	} else {
+		d.literal = make([]prob, size)
+	}
+
+	return nil
+}
+
+// reset resets every probability and the state of the decoder.
+func (d *lzmaDecoder) reset() {
+	resetProbs(d.literal)
+	resetProbs(d.isMatch[:])
+	resetProbs(d.isRep[:])
+	resetProbs(d.isRepG0[:])
+	resetProbs(d.isRepG1[:])
+	resetProbs(d.isRepG2[:])
+	resetProbs(d.isRep0Long[:])
+	resetProbs(d.posSpecial[:])
+	resetProbs(d.align[:])
+
+	for i := range d.posSlot {
+		resetProbs(d.posSlot[i][:])
+	}
+
+	d.length.reset()
+	d.repLength.reset()
+
+	d.state = 0
+	d.rep = [4]uint32{}
+}
+
+// decode decodes exactly size bytes from rc into w. Matches may not cross the end of the chunk.
+//
+//nolint:cyclop,funlen,mnd // reason: the LZMA decoding loop.
+func (d *lzmaDecoder) decode(rc *rangeDecoder, w *window, size int) error {
+	posMask := uint32(1)<<d.pb - 1
+
+	for end := w.total + int64(size); w.total < end; {
+		if rc.extra {
+			return ErrCorrupt
+		}
+
+		posState := uint32(w.total) & posMask //nolint:gosec // reason: only the low bits are used.
+
+		if rc.bit(&d.isMatch[d.state<<lzmaPosBitsMax+posState]) == 0 {
+			d.decodeLiteral(rc, w)
+			continue
+		}
+
+		var length uint32
+
+		switch {
+		case rc.bit(&d.isRep[d.state]) == 0:
+			d.rep[3], d.rep[2], d.rep[1] = d.rep[2], d.rep[1], d.rep[0]
+			length = d.length.decode(rc, posState)
+
+			if d.state < 7 {
+				d.state = 7
+			} else {
+				d.state = 10
+			}
+
+			d.rep[0] = d.decodeDistance(rc, length)
+			if d.rep[0] == 0xFFFFFFFF {
+				// LZMA2 does not use end markers.
+				return ErrCorrupt
+			}
+		case rc.bit(&d.isRepG0[d.state]) == 0:
+			if rc.bit(&d.isRep0Long[d.state<<lzmaPosBitsMax+posState]) == 0 {
+				if d.state < 7 {
+					d.state = 9
+				} else {
+					d.state = 11
+				}
+
+				if !w.hasDistance(d.rep[0]) {
+					return ErrCorrupt
+				}
+
+				w.put(w.get(d.rep[0]))
+
+				continue
+			}
+
+			length = d.repeat(rc, posState)
+		default:
+			var distance uint32
+
+			switch {
+			case rc.bit(&d.isRepG1[d.state]) == 0:
+				distance = d.rep[1]
+			case rc.bit(&d.isRepG2[d.state]) == 0:
+				distance = d.rep[2]
+				d.rep[2] = d.rep[1]
+			default:
+				distance = d.rep[3]
+				d.rep[3], d.rep[2] = d.rep[2], d.rep[1]
+			}
+
+			d.rep[1], d.rep[0] = d.rep[0], distance
+			length = d.repeat(rc, posState)
+		}
+
+		length += lzmaMatchMinLen
+		if int64(length) > end-w.total || !w.hasDistance(d.rep[0]) {
+			return ErrCorrupt
+		}
+
+		w.copyMatch(d.rep[0], int(length))
+	}
+
+	return nil
+}
+
+func (d *lzmaDecoder) repeat(rc *rangeDecoder, posState uint32) uint32 {
+	if d.state < 7 { //nolint:mnd // reason: literal states.
+		d.state = 8
+	} else {
+		d.state = 11
+	}
+
+	return d.repLength.decode(rc, posState)
+}
+
+//nolint:mnd // reason: LZMA literal coding.
+func (d *lzmaDecoder) decodeLiteral(rc *rangeDecoder, w *window) {
+	var previous uint32
+	if w.size > 0 {
+		previous = uint32(w.get(0))
+	}
+
+	litState := (uint32(w.total)&(1<<d.lp-1))<<d.lc + previous>>(8-d.lc) //nolint:gosec // reason: only the low bits are used.
+	probs := d.literal[lzmaLiteralSize*litState:]
+	symbol := uint32(1)
+
+	if d.state >= 7 && w.hasDistance(d.rep[0]) {
+		match := uint32(w.get(d.rep[0]))
+
+		for symbol < 0x100 {
+			matchBit := match >> 7 & 1
+			match <<= 1
+			bit := rc.bit(&probs[(1+matchBit)<<8+symbol])
+			symbol = symbol<<1 | bit
+
+			if matchBit != bit {
+				break
+			}
+		}
+	}
+
+	for symbol < 0x100 {
+		symbol = symbol<<1 | rc.bit(&probs[symbol])
+	}
+
+	w.put(byte(symbol))
+
+	switch {
+	case d.state < 4:
+		d.state = 0
+	case d.state < 10:
+		d.state -= 3
+	default:
+		d.state -= 6
+	}
+}
+
+//nolint:mnd // reason: LZMA distance coding.
+func (d *lzmaDecoder) decodeDistance(rc *rangeDecoder, length uint32) uint32 {
+	slot := rc.bitTree(d.posSlot[min(length, lzmaLenStates-1)][:], 6)
+	if slot < 4 {
+		return slot
+	}
+
+	bits := uint(slot>>1 - 1)
+	distance := (2 | slot&1) << bits
+
+	if slot < lzmaEndPosModel {
+		return distance + rc.reverseBitTree(d.posSpecial[distance-slot:], bits)
+	}
+
+	distance += rc.direct(bits-lzmaAlignBits) << lzmaAlignBits
+
+	return distance + rc.reverseBitTree(d.align[:], lzmaAlignBits)
+}
+
+func resetProbs(probs []prob) {
+	for i := range probs {
+		probs[i] = probInit
+	}
+}
@@ -0,0 +1,247 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package xz
+
+import (
+	"io"
+)
+
+const (
+	lzma2MaxChunk   = 1 << 21
+	lzma2DictMax    = 40
+	lzma2MinDict    = 1 << 12
+	lzma2PropsReset = 0xC0
+	lzma2StateReset = 0xA0
+	lzma2DictReset  = 0xE0
+)
+
+// window is the dictionary of the decoder. Bytes are kept until they are read,
+// which is why it is at least as large as the largest chunk.
+type window struct {
+	buf     []byte
+	pos     int
+	size    int
+	pending int
+	total   int64
+}
+
+func newWindow(dictSize uint32) *window {
+	return &window{buf: make([]byte, max(int(dictSize), lzma2MaxChunk)), pos: 0, size: 0, pending: 0, total: 0}
+}
+
+func (w *window) reset() {
+	w.pos, w.size, w.total = 0, 0, 0
+}
+
+// hasDistance returns true if a match at distance+1 bytes back is inside the window.
+func (w *window) hasDistance(distance uint32) bool {
+	return int64(distance) < int64(w.size)
+}
+
+// get returns the byte distance+1 bytes before the current position.
+func (w *window) get(distance uint32) byte {
+	i := w.pos - int(distance) - 1
+	if i < 0 {
+		i += len(w.buf)
+	}
+
+	return w.buf[i]
+}
+
+func (w *window) put(b byte) {
+	w.buf[w.pos] = b
+
+	w.pos++
+	if w.pos == len(w.buf) {
+		w.pos = 0
+	}
+
+	if w.size < len(w.buf) {
+		w.size++
+	}
+
+	w.pending++
+	w.total++
+}
+
+func (w *window) copyMatch(distance uint32, length int) {
+	for range length {
+		w.put(w.get(distance))
+	}
+}
+
+// write copies uncompressed data into the window.
+func (w *window) write(data []byte) {
+	for _, b := range data {
+		w.put(b)
+	}
+}
+
+// read copies pending bytes into p.
+func (w *window) read(p []byte) int {
+	n := min(len(p), w.pending)
+
+	start := w.pos - w.pending
+	if start < 0 {
+		start += len(w.buf)
+	}
+
+	copied := copy(p[:n], w.buf[start:])
+	copy(p[copied:n], w.buf)
+
+	w.pending -= n
+
+	return n
+}
+
+// lzma2Decoder decodes the chunks of an LZMA2 filter into its window.
+type lzma2Decoder struct {
+	r      io.Reader
+	window *window
+	lzma   lzmaDecoder
+	rc     rangeDecoder
+	packed []byte
+
+	needDictReset bool
+	needProps     bool
+}
+
+// dictionarySize returns the dictionary size encoded in the LZMA2 filter properties.
+func dictionarySize(props byte) (uint32, error) {
+	if props > lzma2DictMax {
+		return 0, ErrCorrupt
+	}
+
+	if props == lzma2DictMax {
+		return 0xFFFFFFFF, nil
+	}
+
+	return (2 | uint32(props)&1) << (props/2 + 11), nil //nolint:mnd // reason: dictionary size encoding.
+}
+
+func newLZMA2Decoder(r io.Reader, dictSize uint32) *lzma2Decoder {
+	return &lzma2Decoder{ //nolint:exhaustruct // reason: lzma and rc are set by the first chunk.
+		r:             r,
+		window:        newWindow(max(dictSize, lzma2MinDict)),
+		needDictReset: true,
+		needProps:     true,
+	}
+}
+
+// chunk decodes the next chunk into the window. It returns io.EOF at the end marker.
+//
+//nolint:cyclop,mnd // reason: chunk control byte.
+func (d *lzma2Decoder) chunk() error {
+	var header [6]byte
+
+	if err := readFull(d.r, header[:1]); err != nil {
+		return err
+	}
+
+	control := header[0]
+
+	switch {
+	case control == 0x00:
+		return io.EOF
+	case control >= lzma2DictReset || control == 0x01:
+		d.needProps = true
+		d.needDictReset = false
+		d.window.reset()
+	case d.needDictReset:
+		return ErrCorrupt
+	}
+
+	if control < 0x80 {
+		if control > 0x02 {
+			return ErrCorrupt
+		}
+
+		if err := readFull(d.r, header[1:3]); err != nil {
+			return err
+		}
+
+		data, err := d.readData(int(header[1])<<8 | int(header[2]) + 1)
+		if err != nil {
+			return err
+		}
+
+		d.window.write(data)
+
+		return nil
+	}
+
+	if err := readFull(d.r, header[1:5]); err != nil {
+		return err
+	}
+
+	unpacked := int(control&0x1F)<<16 | int(header[1])<<8 | int(header[2]) + 1
+	packed := int(header[3])<<8 | int(header[4]) + 1
+
+	switch {
+	case control >= lzma2PropsReset:
+		if err := readFull(d.r, header[5:6]); err != nil {
+			return err
+		}
+
+		if err := d.lzma.setProperties(header[5]); err != nil {
+			return err
+		}
+
+		d.needProps = false
+
+		d.lzma.reset()
+	case d.needProps:
+		return ErrCorrupt
+	case control >= lzma2StateReset:
+		d.lzma.reset()
+	}
+
+	data, err := d.readData(packed)
+	if err != nil {
+		return err
+	}
+
+	if err := d.rc.init(data); err != nil {
+		return err
+	}
+
+	if err := d.lzma.decode(&d.rc, d.window, unpacked); err != nil {
+		return err
+	}
+
+	if !d.rc.finished() {
+		return ErrCorrupt
+	}
+
+	return nil
+}
+
+// readData reads size bytes of chunk data.
+func (d *lzma2Decoder) readData(size int) ([]byte, error) {
+	if cap(d.packed) < size {
+		d.packed = make([]byte, size)
+	}
+
+	data := d.packed[:size]
+	if err := readFull(d.r, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
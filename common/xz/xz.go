@@ -0,0 +1,483 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+// Package xz decompresses .xz files that use the LZMA2 filter, which is what xz and 7-Zip write by default.
+package xz
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"hash/crc32"
+	"hash/crc64"
+	"io"
+)
+
+const (
+	headerSize   = 12
+	footerSize   = 12
+	filterLZMA2  = 0x21
+	checkNone    = 0x00
+	checkCRC32   = 0x01
+	checkCRC64   = 0x04
+	checkSHA256  = 0x0A
+	maxCheckType = 0x0F
+)
+
+var (
+	headerMagic = []byte{0xFD, '7', 'z', 'X', 'Z', 0x00} //nolint:gochecknoglobals // reason: magic bytes are constant.
+	footerMagic = []byte{'Y', 'Z'}                       //nolint:gochecknoglobals // reason: magic bytes are constant.
+)
+
+// Reader decompresses an xz stream. Concatenated streams and stream padding are supported.
+type Reader struct {
+	r      *countingReader
+	flags  [2]byte
+	check  hash.Hash
+	blocks []blockRecord
+
+	block        *lzma2Decoder
+	blockStart   int64
+	indexSize    int64
+	uncompressed int64
+	headerSizes  blockRecord
+
+	err error
+}
+
+type blockRecord struct {
+	unpadded     int64
+	uncompressed int64
+}
+
+// NewReader reads the stream header of r and returns a Reader of the decompressed data.
+func NewReader(r io.Reader) (*Reader, error) {
+	z := &Reader{r: &countingReader{r: bufio.NewReader(r), n: 0, crc: nil}} //nolint:exhaustruct // reason: set by the stream header.
+
+	if err := z.readHeader(); err != nil {
+		return nil, err
+	}
+
+	return z, nil
+}
+
+// Read reads decompressed data into p.
+func (z *Reader) Read(p []byte) (int, error) {
+	for z.err == nil {
+		if z.block != nil {
+			if n := z.block.window.read(p); n > 0 {
+				z.check.Write(p[:n])
+				z.uncompressed += int64(n)
+
+				return n, nil
+			}
+
+			if err := z.block.chunk(); errors.Is(err, io.EOF) {
+				z.err = z.finishBlock()
+			} else if err != nil {
+				z.err = err
+			}
+
+			continue
+		}
+
+		z.err = z.next()
+	}
+
+	return 0, z.err
+}
+
+// next reads the next block header, or the index and footer followed by the next stream if there is one.
+func (z *Reader) next() error {
+	size, err := z.r.ReadByte()
+	if err != nil {
+		return unexpected(err)
+	}
+
+	if size == 0 {
+		if err := z.readIndex(); err != nil {
+			return err
+		}
+
+		if err := z.readFooter(); err != nil {
+			return err
+		}
+
+		return z.nextStream()
+	}
+
+	return z.readBlockHeader(size)
+}
+
+func (z *Reader) readHeader() error {
+	var header [headerSize]byte
+
+	if err := readFull(z.r, header[:]); err != nil {
+		return err
+	}
+
+	if !bytes.Equal(header[:6], headerMagic) || header[6] != 0 || header[7] > maxCheckType {
+		return ErrInvalidHeader
+	}
+
+	if crc32.ChecksumIEEE(header[6:8]) != binary.LittleEndian.Uint32(header[8:]) {
+		return ErrInvalidHeader
+	}
+
+	z.flags = [2]byte{header[6], header[7]}
+	z.blocks = nil
+
+	switch z.flags[1] {
+	case checkCRC32:
+		z.check = crc32.NewIEEE()
+	case checkCRC64:
+		z.check = crc64.New(crc64.MakeTable(crc64.ECMA))
+	case checkSHA256:
+		z.check = sha256.New()
+	default:
+		z.check = discardHash{size: checkSize(z.flags[1])}
+	}
+
+	return nil
+}
+
+//nolint:cyclop // reason: block header fields.
+func (z *Reader) readBlockHeader(size byte) error {
+	header := make([]byte, (int(size)+1)*4) //nolint:mnd // reason: header size is stored in multiples of 4.
+	header[0] = size
+
+	start := z.r.n - 1
+
+	if err := readFull(z.r, header[1:]); err != nil {
+		return err
+	}
+
+	body := header[:len(header)-4]
+	if crc32.ChecksumIEEE(body) != binary.LittleEndian.Uint32(header[len(header)-4:]) {
+		return ErrInvalidBlock
+	}
+
+	flags := body[1]
+	if flags&0x3C != 0 { //nolint:mnd // reason: reserved bits.
+		return ErrInvalidBlock
+	}
+
+	reader := bytes.NewReader(body[2:])
+	z.headerSizes = blockRecord{unpadded: -1, uncompressed: -1}
+
+	if flags&0x40 != 0 { //nolint:mnd // reason: compressed size present.
+		compressed, err := readVarint(reader)
+		if err != nil || compressed == 0 {
+			return ErrInvalidBlock
+		}
+
+		z.headerSizes.unpadded = int64(compressed) + int64(len(header)) + int64(checkSize(z.flags[1])) //nolint:gosec // reason: sizes fit.
+	}
+
+	if flags&0x80 != 0 { //nolint:mnd // reason: uncompressed size present.
+		uncompressed, err := readVarint(reader)
+		if err != nil {
+			return ErrInvalidBlock
+		}
+
+		z.headerSizes.uncompressed = int64(uncompressed) //nolint:gosec // reason: sizes fit.
+	}
+
+	if flags&0x03 != 0 {
+		return ErrUnsupportedFilter
+	}
+
+	id, err := readVarint(reader)
+	if err != nil {
+		return ErrInvalidBlock
+	}
+
+	propsSize, err := readVarint(reader)
+	if err != nil {
+		return ErrInvalidBlock
+	}
+
+	if id != filterLZMA2 || propsSize != 1 {
+		return ErrUnsupportedFilter
+	}
+
+	props, err := reader.ReadByte()
+	if err != nil {
+		return ErrInvalidBlock
+	}
+
+	dictSize, err := dictionarySize(props)
+	if err != nil {
+		return err
+	}
+
+	for reader.Len() > 0 {
+		if b, _ := reader.ReadByte(); b != 0 {
+			return ErrInvalidBlock
+		}
+	}
+
+	z.check.Reset()
+	z.block = newLZMA2Decoder(z.r, dictSize)
+	z.blockStart = start
+	z.uncompressed = 0
+
+	return nil
+}
+
+// finishBlock reads the block padding and check, and compares them with the decompressed data.
+func (z *Reader) finishBlock() error {
+	unpadded := z.r.n - z.blockStart
+
+	for z.r.n%4 != 0 {
+		if b, err := z.r.ReadByte(); err != nil {
+			return unexpected(err)
+		} else if b != 0 {
+			return ErrCorrupt
+		}
+	}
+
+	stored := make([]byte, checkSize(z.flags[1]))
+	if err := readFull(z.r, stored); err != nil {
+		return err
+	}
+
+	if _, ok := z.check.(discardHash); !ok && !bytes.Equal(stored, checksum(z.check)) {
+		return ErrChecksum
+	}
+
+	record := blockRecord{unpadded: unpadded + int64(len(stored)), uncompressed: z.uncompressed}
+
+	if (z.headerSizes.unpadded >= 0 && z.headerSizes.unpadded != record.unpadded) ||
+		(z.headerSizes.uncompressed >= 0 && z.headerSizes.uncompressed != record.uncompressed) {
+		return ErrCorrupt
+	}
+
+	z.blocks = append(z.blocks, record)
+	z.block = nil
+
+	return nil
+}
+
+// readIndex reads the index after the indicator byte and compares its records with the decoded blocks.
+func (z *Reader) readIndex() error {
+	start := z.r.n - 1
+	z.r.crc = crc32.NewIEEE()
+	z.r.crc.Write([]byte{0})
+
+	count, err := readVarint(z.r)
+	if err != nil || count != uint64(len(z.blocks)) {
+		return ErrInvalidIndex
+	}
+
+	for _, block := range z.blocks {
+		unpadded, err := readVarint(z.r)
+		if err != nil {
+			return ErrInvalidIndex
+		}
+
+		uncompressed, err := readVarint(z.r)
+		if err != nil {
+			return ErrInvalidIndex
+		}
+
+		if int64(unpadded) != block.unpadded || int64(uncompressed) != block.uncompressed { //nolint:gosec // reason: sizes fit.
+			return ErrInvalidIndex
+		}
+	}
+
+	for z.r.n%4 != 0 {
+		if b, err := z.r.ReadByte(); err != nil || b != 0 {
+			return ErrInvalidIndex
+		}
+	}
+
+	sum := z.r.crc.Sum32()
+	z.r.crc = nil
+
+	var stored [4]byte
+	if err := readFull(z.r, stored[:]); err != nil {
+		return err
+	}
+
+	if binary.LittleEndian.Uint32(stored[:]) != sum {
+		return ErrInvalidIndex
+	}
+
+	z.indexSize = z.r.n - start
+
+	return nil
+}
+
+func (z *Reader) readFooter() error {
+	var footer [footerSize]byte
+
+	if err := readFull(z.r, footer[:]); err != nil {
+		return err
+	}
+
+	if !bytes.Equal(footer[10:], footerMagic) || !bytes.Equal(footer[8:10], z.flags[:]) {
+		return ErrInvalidFooter
+	}
+
+	if crc32.ChecksumIEEE(footer[4:10]) != binary.LittleEndian.Uint32(footer[:4]) {
+		return ErrInvalidFooter
+	}
+
+	if (int64(binary.LittleEndian.Uint32(footer[4:8]))+1)*4 != z.indexSize { //nolint:mnd // reason: backward size is stored in multiples of 4.
+		return ErrInvalidFooter
+	}
+
+	return nil
+}
+
+// nextStream skips stream padding and reads the header of the next stream. It returns io.EOF at the end of the input.
+func (z *Reader) nextStream() error {
+	for {
+		next, err := z.r.r.Peek(1)
+		if errors.Is(err, io.EOF) {
+			return io.EOF
+		} else if err != nil {
+			return err
+		}
+
+		if next[0] != 0 {
+			break
+		}
+
+		var padding [4]byte
+		if err := readFull(z.r, padding[:]); err != nil {
+			return err
+		}
+
+		if padding != [4]byte{} {
+			return ErrInvalidHeader
+		}
+	}
+
+	return z.readHeader()
+}
+
+// countingReader counts the bytes read and optionally hashes them.
+type countingReader struct {
+	r   *bufio.Reader
+	n   int64
+	crc hash.Hash32
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	if err != nil && n == 0 {
+		return 0, err
+	}
+
+	c.n += int64(n)
+
+	if c.crc != nil {
+		c.crc.Write(p[:n])
+	}
+
+	return n, nil
+}
+
+func (c *countingReader) ReadByte() (byte, error) {
+	var b [1]byte
+
+	if _, err := io.ReadFull(c, b[:]); err != nil {
+		return 0, err
+	}
+
+	return b[0], nil
+}
+
+// discardHash stands in for check types that are skipped.
+type discardHash struct {
+	size int
+}
+
+func (discardHash) Write(p []byte) (int, error) { return len(p), nil }
+func (discardHash) Sum(b []byte) []byte         { return b }
+func (discardHash) Reset()                      {}
+func (h discardHash) Size() int                 { return h.size }
+func (discardHash) BlockSize() int              { return 1 }
+
+// checkSize returns the size of the check of the given type.
+func checkSize(kind byte) int {
+	if kind == checkNone {
+		return 0
+	}
+
+	return 4 << ((kind - 1) / 3) //nolint:mnd // reason: check sizes are 4, 8, 16, 32 and 64 bytes.
+}
+
+// checksum returns the check in the byte order it is stored in.
+func checksum(check hash.Hash) []byte {
+	sum := check.Sum(nil)
+
+	switch check.(type) {
+	case hash.Hash32, hash.Hash64:
+		for i, j := 0, len(sum)-1; i < j; i, j = i+1, j-1 {
+			sum[i], sum[j] = sum[j], sum[i]
+		}
+	}
+
+	return sum
+}
+
+func readVarint(r io.ByteReader) (uint64, error) {
+	const maxBytes = 9
+
+	var value uint64
+
+	for i := range maxBytes {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+
+		value |= uint64(b&0x7F) << (7 * i) //nolint:mnd // reason: 7 bits per byte.
+
+		if b&0x80 == 0 {
+			if i > 0 && b == 0 {
+				return 0, ErrCorrupt
+			}
+
+			return value, nil
+		}
+	}
+
+	return 0, ErrCorrupt
+}
+
+func readFull(r io.Reader, buf []byte) error {
+	_, err := io.ReadFull(r, buf)
+
+	return unexpected(err)
+}
+
+// unexpected turns io.EOF into io.ErrUnexpectedEOF since the input may only end after a stream.
+func unexpected(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
@@ -0,0 +1,102 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package xz_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/hkmh223/pd2mm/common/xz"
+)
+
+// sample is 300 lines of "pd2mm line <n>" compressed by xz with a CRC64 check.
+const sample = "" +
+	"/Td6WFoAAATm1rRGAgAhARYAAAB0L+Wj4BEnAR1dADgZAkVKeBLSKtUcAqQcvWAP4f7tCrAMLC8vSgt+WGkCOaKQj41sXFw5bYUA" +
+	"Oqt52MvDYWQRdJI1nIMDvz4id2IncqZrvhj4+Y+AvjfITNCWHJahgXMKEVeW2FsfqUQPAjZ3b953VqBTTv3mLb0bCS85RTKO9GM6" +
+	"RIl5fEGth5OixChHEizBPHkBtDyR+W82iPb+UwxMU4bDZvrya41FYz0bP1j0kIiwso5xlsLzDF6BwDT3s/Tq1wNasGjcF6lvWE1Q" +
+	"n1r0iyrzdIGMhVhhcl78Ozz3AfED2q6H7CsKa/V+kt1uaTZNCWJK3KURdnBlgInHYgBMcdzi6VNPWGb36vk4zqllLdMbF501kMKB" +
+	"e65OiXBrZg98TytxPCc6AAAAAABoH0QHr9yXnQABuQKoIgAAZGZGN7HEZ/sCAAAAAARZWg=="
+
+func expected() []byte {
+	var buf strings.Builder
+
+	for i := 1; i <= 300; i++ {
+		fmt.Fprintf(&buf, "pd2mm line %d\n", i)
+	}
+
+	return []byte(buf.String())
+}
+
+func decompress(data []byte) ([]byte, error) {
+	reader, err := xz.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	return io.ReadAll(reader)
+}
+
+func TestReader(t *testing.T) {
+	t.Parallel()
+
+	data, err := base64.StdEncoding.DecodeString(sample)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// concatenated streams with stream padding in between.
+	data = append(append(append([]byte{}, data...), 0, 0, 0, 0), data...)
+
+	result, err := decompress(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(result, append(expected(), expected()...)) {
+		t.Fatal("unexpected decompressed data")
+	}
+}
+
+func TestReaderCorrupt(t *testing.T) {
+	t.Parallel()
+
+	data, err := base64.StdEncoding.DecodeString(sample)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := decompress(data[:len(data)/2]); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("expected unexpected EOF, got %v", err)
+	}
+
+	data[40] ^= 0xFF
+
+	if _, err := decompress(data); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if _, err := xz.NewReader(strings.NewReader("not an xz file")); !errors.Is(err, xz.ErrInvalidHeader) {
+		t.Fatalf("expected an invalid header, got %v", err)
+	}
+}
@@ -0,0 +1,347 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package zip
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/sha1" //nolint:gosec // reason: WinZip AES derives its keys with SHA-1.
+	"encoding/binary"
+	"errors"
+	"hash"
+	"hash/crc32"
+	"io"
+)
+
+var (
+	ErrPasswordRequired      = errors.New("the archive is encrypted and needs a password")
+	ErrWrongPassword         = errors.New("the password of the archive is wrong")
+	ErrUnsupportedEncryption = errors.New("the archive uses an unsupported encryption method")
+	ErrChecksum              = errors.New("the checksum of the extracted file does not match")
+)
+
+const (
+	flagEncrypted      = 0x1
+	flagDataDescriptor = 0x8
+
+	methodAES       = 99
+	extraAES        = 0x9901
+	aesExtraSize    = 7
+	aesVersion1     = 1
+	aesAuthSize     = 10
+	aesVerifierSize = 2
+	aesIterations   = 1000
+
+	zipCryptoHeaderSize = 12
+)
+
+// IsEncrypted returns true if any file in the zip archive at src is encrypted.
+func IsEncrypted(src string) (bool, error) {
+	read, err := zip.OpenReader(src)
+	if err != nil {
+		return false, err
+	}
+	defer read.Close()
+
+	for _, file := range read.File {
+		if file.Flags&flagEncrypted != 0 {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// IsUnsupported returns true if err is a compression or encryption method this package can not read,
+// 7-Zip reads most of them.
+func IsUnsupported(err error) bool {
+	return errors.Is(err, zip.ErrAlgorithm) || errors.Is(err, ErrUnsupportedEncryption)
+}
+
+// open opens a file of a zip archive, decrypting it with password if it is encrypted.
+// ZipCrypto and WinZip AES are supported, the content of an encrypted file is only checked once it is read to the end.
+func open(file *zip.File, password string) (io.ReadCloser, error) {
+	if file.Flags&flagEncrypted == 0 && file.Method != methodAES {
+		return file.Open()
+	}
+
+	if password == "" {
+		return nil, ErrPasswordRequired
+	}
+
+	raw, err := file.OpenRaw()
+	if err != nil {
+		return nil, err
+	}
+
+	if file.Method == methodAES {
+		return openAES(file, raw, password)
+	}
+
+	return openZipCrypto(file, raw, password)
+}
+
+// openZipCrypto decrypts a file encrypted with the traditional PKWARE cipher.
+func openZipCrypto(file *zip.File, raw io.Reader, password string) (io.ReadCloser, error) {
+	keys := newZipCryptoKeys(password)
+
+	header := make([]byte, zipCryptoHeaderSize)
+	if _, err := io.ReadFull(raw, header); err != nil {
+		return nil, err
+	}
+
+	keys.decrypt(header)
+
+	// The last byte of the header is the high byte of the CRC, or of the modification time if a data descriptor follows.
+	check := byte(file.CRC32 >> 24) //nolint:mnd // reason: high byte.
+	if file.Flags&flagDataDescriptor != 0 {
+		check = byte(file.ModifiedTime >> 8) //nolint:mnd // reason: high byte.
+	}
+
+	if header[zipCryptoHeaderSize-1] != check {
+		return nil, ErrWrongPassword
+	}
+
+	return decompress(file, &zipCryptoReader{r: raw, keys: keys}, true)
+}
+
+// openAES decrypts a file encrypted with WinZip AES, the actual compression method is stored in its extra field.
+func openAES(file *zip.File, raw io.Reader, password string) (io.ReadCloser, error) {
+	version, strength, method, ok := aesExtra(file.Extra)
+	if !ok {
+		return nil, ErrUnsupportedEncryption
+	}
+
+	keySize := map[byte]int{1: 16, 2: 24, 3: 32}[strength] //nolint:mnd // reason: AES-128, AES-192 and AES-256.
+	if keySize == 0 {
+		return nil, ErrUnsupportedEncryption
+	}
+
+	saltSize := keySize / 2 //nolint:mnd // reason: the salt is half the key size.
+	overhead := uint64(saltSize + aesVerifierSize + aesAuthSize)
+
+	if file.CompressedSize64 < overhead {
+		return nil, zip.ErrFormat
+	}
+
+	salt := make([]byte, saltSize+aesVerifierSize)
+	if _, err := io.ReadFull(raw, salt); err != nil {
+		return nil, err
+	}
+
+	key, err := pbkdf2.Key(sha1.New, password, salt[:saltSize], aesIterations, keySize*2+aesVerifierSize)
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(key[keySize*2:], salt[saltSize:]) {
+		return nil, ErrWrongPassword
+	}
+
+	block, err := aes.NewCipher(key[:keySize])
+	if err != nil {
+		return nil, err
+	}
+
+	size := int64(file.CompressedSize64 - overhead) //nolint:gosec // reason: sizes of zip entries fit in int64.
+	reader := &aesReader{
+		r:      io.LimitReader(raw, size),
+		tail:   raw,
+		stream: newAESCounter(block),
+		mac:    hmac.New(sha1.New, key[keySize:keySize*2]),
+		err:    nil,
+	}
+
+	// AE-2 stores no CRC, the authentication code protects the content instead.
+	return decompress(&zip.File{FileHeader: zip.FileHeader{Method: method, CRC32: file.CRC32}}, reader, version == aesVersion1)
+}
+
+// aesExtra returns the version, strength and compression method stored in the WinZip AES extra field.
+func aesExtra(extra []byte) (uint16, byte, uint16, bool) {
+	for len(extra) >= 4 {
+		id := binary.LittleEndian.Uint16(extra)
+		size := int(binary.LittleEndian.Uint16(extra[2:]))
+		extra = extra[4:]
+
+		if size > len(extra) {
+			break
+		}
+
+		if id == extraAES && size >= aesExtraSize {
+			return binary.LittleEndian.Uint16(extra), extra[4], binary.LittleEndian.Uint16(extra[5:]), true
+		}
+
+		extra = extra[size:]
+	}
+
+	return 0, 0, 0, false
+}
+
+// decompress returns a reader of the uncompressed content of a decrypted file, checking the CRC at the end if check is true.
+func decompress(file *zip.File, r io.Reader, check bool) (io.ReadCloser, error) {
+	var content io.ReadCloser
+
+	switch file.Method {
+	case zip.Store:
+		content = io.NopCloser(r)
+	case zip.Deflate:
+		content = flate.NewReader(r)
+	default:
+		return nil, zip.ErrAlgorithm
+	}
+
+	if !check {
+		return content, nil
+	}
+
+	return &checksumReader{r: content, hash: crc32.NewIEEE(), crc: file.CRC32}, nil
+}
+
+// zipCryptoKeys is the state of the traditional PKWARE cipher.
+type zipCryptoKeys [3]uint32
+
+func newZipCryptoKeys(password string) *zipCryptoKeys {
+	keys := &zipCryptoKeys{0x12345678, 0x23456789, 0x34567890}
+
+	for i := range len(password) {
+		keys.update(password[i])
+	}
+
+	return keys
+}
+
+func (k *zipCryptoKeys) update(b byte) {
+	k[0] = crc32Update(k[0], b)
+	k[1] = (k[1]+k[0]&0xff)*134775813 + 1 //nolint:mnd // reason: constants of the cipher.
+	k[2] = crc32Update(k[2], byte(k[1]>>24))
+}
+
+func (k *zipCryptoKeys) decrypt(data []byte) {
+	for i := range data {
+		temp := k[2] | 2 //nolint:mnd // reason: constants of the cipher.
+		data[i] ^= byte((temp * (temp ^ 1)) >> 8)
+		k.update(data[i])
+	}
+}
+
+func crc32Update(crc uint32, b byte) uint32 {
+	return crc32.IEEETable[byte(crc)^b] ^ crc>>8
+}
+
+type zipCryptoReader struct {
+	r    io.Reader
+	keys *zipCryptoKeys
+}
+
+func (z *zipCryptoReader) Read(p []byte) (int, error) {
+	n, err := z.r.Read(p)
+	z.keys.decrypt(p[:n])
+
+	return n, err
+}
+
+// aesCounter is AES in CTR mode with the little-endian counter starting at 1 used by WinZip.
+type aesCounter struct {
+	block   cipher.Block
+	counter uint64
+	stream  [aes.BlockSize]byte
+	used    int
+}
+
+func newAESCounter(block cipher.Block) *aesCounter {
+	return &aesCounter{block: block, counter: 0, stream: [aes.BlockSize]byte{}, used: aes.BlockSize}
+}
+
+func (a *aesCounter) XORKeyStream(data []byte) {
+	for i := range data {
+		if a.used == aes.BlockSize {
+			a.counter++
+
+			var counter [aes.BlockSize]byte
+
+			binary.LittleEndian.PutUint64(counter[:], a.counter)
+			a.block.Encrypt(a.stream[:], counter[:])
+			a.used = 0
+		}
+
+		data[i] ^= a.stream[a.used]
+		a.used++
+	}
+}
+
+// aesReader decrypts the content of a WinZip AES file and checks the authentication code that follows it.
+type aesReader struct {
+	r      io.Reader
+	tail   io.Reader
+	stream *aesCounter
+	mac    hash.Hash
+	err    error
+}
+
+func (a *aesReader) Read(p []byte) (int, error) {
+	if a.err != nil {
+		return 0, a.err
+	}
+
+	n, err := a.r.Read(p)
+	a.mac.Write(p[:n])
+	a.stream.XORKeyStream(p[:n])
+
+	if !errors.Is(err, io.EOF) {
+		return n, err
+	}
+
+	code := make([]byte, aesAuthSize)
+	if _, err := io.ReadFull(a.tail, code); err != nil {
+		return n, err
+	}
+
+	a.err = io.EOF
+	if !hmac.Equal(code, a.mac.Sum(nil)[:aesAuthSize]) {
+		a.err = ErrChecksum
+	}
+
+	return n, a.err
+}
+
+type checksumReader struct {
+	r    io.ReadCloser
+	hash hash.Hash32
+	crc  uint32
+}
+
+func (c *checksumReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.hash.Write(p[:n])
+
+	if errors.Is(err, io.EOF) && c.hash.Sum32() != c.crc {
+		return n, ErrChecksum
+	}
+
+	return n, err
+}
+
+func (c *checksumReader) Close() error {
+	return c.r.Close()
+}
@@ -0,0 +1,107 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package zip_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hkmh223/pd2mm/common/zip"
+)
+
+// encrypted is a.txt containing "hello encrypted world\n", zipped with ZipCrypto and the password "secret".
+const encrypted = "" +
+	"UEsDBAoACQAAALoWU1304eFqIgAAABYAAAAFAAAAYS50eHQpksi3NdlKWW4SWBYUXF18lHK2WqMepX0FlkE3f62Ex+06UEsHCPTh" +
+	"4WoiAAAAFgAAAFBLAQIeAwoACQAAALoWU1304eFqIgAAABYAAAAFAAAAAAAAAAEAAACkgQAAAABhLnR4dFBLBQYAAAAAAQABADMA" +
+	"AABVAAAAAAA="
+
+func TestUnzipPassword(t *testing.T) {
+	t.Parallel()
+
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "encrypted.zip")
+
+	if err := os.WriteFile(src, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if ok, err := zip.IsEncrypted(src); err != nil || !ok {
+		t.Fatalf("expected an encrypted archive, got %t %v", ok, err)
+	}
+
+	opts := zip.UnzipOptions{Prefix: "", Password: "", Guard: nil, Messenger: zip.Messenger{AddedFile: func(string) {}}}
+
+	for password, expected := range map[string]error{"": zip.ErrPasswordRequired, "wrong": zip.ErrWrongPassword} {
+		opts.Password = password
+
+		if err := zip.UnzipWithOptions(src, filepath.Join(dir, "fail"), opts); !errors.Is(err, expected) {
+			t.Fatalf("expected %v for password %q, got %v", expected, password, err)
+		}
+	}
+
+	opts.Password = "secret"
+
+	if err := zip.UnzipWithOptions(src, filepath.Join(dir, "out"), opts); err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "out", "a.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(content) != "hello encrypted world\n" {
+		t.Fatalf("unexpected content %q", content)
+	}
+}
+
+// bzip2 is mod.txt containing "{}\n", compressed with bzip2.
+const bzip2 = "" +
+	"UEsDBC4AAAAMAGAcU10GsKHdKAAAAAMAAAAHAAAAbW9kLnR4dEJaaDkxQVkmU1lXyN6WAAAAwIAAEAAKIAAhmBmEYXckU4UJBXyN6WBQSwEC" +
+	"LgMuAAAADABgHFNdBrCh3SgAAAADAAAABwAAAAAAAAAAAAAAgAEAAAAAbW9kLnR4dFBLBQYAAAAAAQABADUAAABNAAAAAAA="
+
+func TestUnzipUnsupportedMethod(t *testing.T) {
+	t.Parallel()
+
+	data, err := base64.StdEncoding.DecodeString(bzip2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "bzip2.zip")
+
+	if err := os.WriteFile(src, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := zip.UnzipOptions{Prefix: "", Password: "", Guard: nil, Messenger: zip.Messenger{AddedFile: func(string) {}}}
+
+	if err := zip.UnzipWithOptions(src, filepath.Join(dir, "out"), opts); !zip.IsUnsupported(err) {
+		t.Fatalf("expected an unsupported method, got %v", err)
+	}
+}
@@ -20,16 +20,26 @@ package zip
 
 import (
 	"archive/zip"
-	"errors"
-	"io"
-	"net/url"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/hkmh223/pd2mm/common/archive"
 	"github.com/hkmh223/pd2mm/common/logger"
 )
 
+// UnzipOptions are the options of UnzipWithOptions.
+type UnzipOptions struct {
+	// Prefix limits extraction to the entries starting with it, and is removed from their paths.
+	Prefix string
+	// Password decrypts encrypted entries, ErrPasswordRequired is returned for them if it is empty.
+	Password string
+	// Guard checks every entry, a guard without limits is used if it is nil.
+	Guard     *archive.Guard
+	Messenger Messenger
+}
+
 func DefaultUnzipMessenger() Messenger {
 	return Messenger{
 		AddedFile: func(path string) {
@@ -43,43 +53,59 @@ func Unzip(src, dest string) error {
 }
 
 func UnzipByPrefixWithMessenger(src, dest, prefix string, msg Messenger) error {
-	step := 1024
+	return UnzipWithOptions(src, dest, UnzipOptions{Prefix: prefix, Password: "", Guard: nil, Messenger: msg})
+}
+
+// UnzipWithOptions unzips the archive at src into dest. Entries leaving dest are rejected and links are skipped,
+// see archive.Guard.
+func UnzipWithOptions(src, dest string, opts UnzipOptions) error {
+	guard := opts.Guard
+	if guard == nil {
+		var err error
+
+		if guard, err = archive.NewGuard(src, dest, archive.Limits{MaxSize: 0, MaxRatio: 0, MaxEntries: 0}); err != nil {
+			return err
+		}
+	}
 
 	read, err := zip.OpenReader(src)
 	if err != nil {
 		return err
 	}
+	defer read.Close()
 
 	for _, file := range read.File {
-		tmp, err := file.Open()
+		if opts.Prefix != "" && !strings.HasPrefix(file.Name, opts.Prefix) {
+			continue
+		}
+
+		name := maybeTrimPrefix(file.Name, opts.Prefix)
+
+		path, err := guard.Path(name)
 		if err != nil {
 			return err
 		}
-		defer tmp.Close()
 
-		if prefix != "" && !strings.HasPrefix(file.Name, prefix) {
+		if file.Mode()&fs.ModeSymlink != 0 {
+			guard.Skip(name)
 			continue
 		}
 
-		name, err := url.QueryUnescape(maybeTrimPrefix(file.Name, prefix))
-		if err != nil {
+		if err := guard.Entry(name, file.UncompressedSize64); err != nil {
 			return err
 		}
 
-		path := filepath.Join(dest, name)
-		msg.AddedFile(path)
+		opts.Messenger.AddedFile(path)
 
 		if file.FileInfo().IsDir() {
 			if err := os.MkdirAll(path, os.ModePerm); err != nil {
 				continue
 			}
-		} else if err := stepCopy(path, tmp, int64(step)); err != nil {
+		} else if err := unzipFile(file, path, opts.Password, guard); err != nil {
 			return err
 		}
 	}
 
-	read.Close()
-
 	return nil
 }
 
@@ -91,28 +117,27 @@ func maybeTrimPrefix(trim, prefix string) string {
 	return trim
 }
 
-func stepCopy(src string, dest io.Reader, step int64) error {
-	if err := os.MkdirAll(filepath.Dir(src), os.ModePerm); err != nil {
+// unzipFile writes the content of file to path through guard.
+func unzipFile(file *zip.File, path, password string, guard *archive.Guard) error {
+	content, err := open(file, password)
+	if err != nil {
 		return err
 	}
+	defer content.Close()
 
-	path, err := os.Create(src)
-	if err != nil {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
 		return err
 	}
 
-	for {
-		_, err := io.CopyN(path, dest, step)
-		if err != nil {
-			if errors.Is(err, io.EOF) {
-				break
-			}
-
-			return err
-		}
+	out, err := os.Create(path)
+	if err != nil {
+		return err
 	}
 
-	path.Close()
+	if err := guard.Copy(file.Name, out, content); err != nil {
+		out.Close()
+		return err
+	}
 
-	return nil
+	return out.Close()
 }
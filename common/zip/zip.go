@@ -24,12 +24,17 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/hkmh223/pd2mm/common/filesystem"
 	"github.com/hkmh223/pd2mm/common/logger"
 )
 
+// FixedTime is the modification time WithFiles writes to every entry so archives are reproducible.
+var FixedTime = time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC) //nolint:gochecknoglobals // reason: FixedTime is constant.
+
 type Messenger struct {
 	AddedFile func(string)
 }
@@ -99,6 +104,69 @@ func WithMessenger(src, dest string, msg Messenger) error {
 	return nil
 }
 
+// WithFiles creates a zip file at dest containing files, stored relative to src under prefix.
+// Entries are sorted and written with FixedTime and fixed permissions, so the same input always produces the same archive.
+func WithFiles(src, dest, prefix string, files []string, msg Messenger) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0o700); err != nil {
+		return err
+	}
+
+	file, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	write := zip.NewWriter(file)
+
+	names := make(map[string]string, len(files))
+
+	for _, path := range files {
+		relative, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+
+		names[path] = strings.TrimPrefix(prefix+"/"+filesystem.Normalize(relative), "/")
+	}
+
+	sorted := slices.Clone(files)
+	slices.SortFunc(sorted, func(a, b string) int {
+		return strings.Compare(names[a], names[b])
+	})
+
+	for _, path := range sorted {
+		if err := addFile(write, path, names[path]); err != nil {
+			return err
+		}
+
+		msg.AddedFile(path)
+	}
+
+	return write.Close()
+}
+
+// addFile writes the file at path to the zip writer as name.
+func addFile(write *zip.Writer, path, name string) error {
+	header := &zip.FileHeader{Name: name, Method: zip.Deflate, Modified: FixedTime} //nolint:exhaustruct // reason: not all fields are needed.
+	header.SetMode(0o644)
+
+	create, err := write.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = io.Copy(create, file)
+
+	return err
+}
+
 func convertPath(path, src string) string {
 	path = trimSrcPrefix(path, src)
 	path = filesystem.Normalize(path)
@@ -19,6 +19,9 @@
 package zip_test
 
 import (
+	stdzip "archive/zip"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/hkmh223/pd2mm/common/zip"
@@ -35,3 +38,44 @@ func TestUnzip(t *testing.T) { //nolint:paralleltest // reason: unzip is dependa
 		t.Fatal(err)
 	}
 }
+
+func TestUnzipNames(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	src := filepath.Join(dir, "names.zip")
+	names := []string{"50%.png", "a+b.txt", "c%20d.txt"}
+
+	file, err := os.Create(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writer := stdzip.NewWriter(file)
+
+	for _, name := range names {
+		if _, err := writer.Create(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := zip.UnzipOptions{Prefix: "", Password: "", Guard: nil, Messenger: zip.Messenger{AddedFile: func(string) {}}}
+
+	if err := zip.UnzipWithOptions(src, filepath.Join(dir, "out"), opts); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range names {
+		if _, err := os.Stat(filepath.Join(dir, "out", name)); err != nil {
+			t.Fatalf("expected %q to keep its name: %v", name, err)
+		}
+	}
+}
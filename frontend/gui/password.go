@@ -0,0 +1,96 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	giu "github.com/AllenDang/giu"
+	"github.com/hkmh223/pd2mm/internal/io"
+	"github.com/hkmh223/pd2mm/internal/lang"
+)
+
+// passwordRequest is an encrypted archive waiting for its password, reply receives it or an empty string to cancel.
+type passwordRequest struct {
+	src   string
+	reply chan string
+}
+
+//nolint:gochecknoglobals // reason: the popup is built every frame.
+var (
+	_passwordRequests = make(chan passwordRequest, 1)
+	_passwordCurrent  *passwordRequest
+	_password         string
+)
+
+// setupPasswordPrompt makes encrypted archives without a configured password ask for it in a popup.
+func setupPasswordPrompt() {
+	io.PasswordPrompt = func(src string) (string, bool) {
+		reply := make(chan string, 1)
+
+		_passwordRequests <- passwordRequest{src: src, reply: reply}
+		giu.Update()
+
+		password := <-reply
+
+		return password, password != ""
+	}
+}
+
+// passwordPopup is the popup that asks for the password of an encrypted archive, it is opened once a request arrives.
+func passwordPopup() giu.Widget {
+	title := lang.Lang("passwordTitle")
+
+	return giu.Layout{
+		giu.Custom(func() {
+			if _passwordCurrent != nil {
+				return
+			}
+
+			select {
+			case request := <-_passwordRequests:
+				_passwordCurrent = &request
+				_password = ""
+
+				giu.OpenPopup(title)
+			default:
+			}
+		}),
+		giu.PopupModal(title).Flags(giu.WindowFlagsAlwaysAutoResize).Layout(
+			giu.Custom(func() {
+				if _passwordCurrent != nil {
+					giu.Label(_passwordCurrent.src).Build()
+				}
+			}),
+			giu.InputText(&_password).Label(lang.Lang("passwordLabel")).Flags(giu.InputTextFlagsPassword),
+			giu.Row(
+				giu.Button(lang.Lang("passwordButton")).OnClick(func() { answerPassword(_password) }),
+				giu.Button(lang.Lang("cancelButton")).OnClick(func() { answerPassword("") }),
+			),
+		),
+	}
+}
+
+// answerPassword sends the password to the waiting extraction and closes the popup.
+func answerPassword(password string) {
+	if _passwordCurrent != nil {
+		_passwordCurrent.reply <- password
+		_passwordCurrent = nil
+	}
+
+	giu.CloseCurrentPopup()
+}
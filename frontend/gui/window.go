@@ -47,6 +47,8 @@ func StartApp(version string, logFile io.Writer) error {
 	logger.RegisterLogger(logFile, _buf)
 	logger.SharedLogger.Info("Initialized!")
 
+	setupPasswordPrompt()
+
 	// ConfigNames either takes a the flag config, otherwise get all configs in the directory.
 	// We want to get all configs.
 	data.Flag.Config = ""
@@ -176,6 +178,8 @@ func window() {
 						giu.Style().SetDisabled(_disabled).To(giu.InputText(&data.Flag.Log).Label(lang.Lang("logLabel"))),
 						giu.Style().SetDisabled(_disabled).To(giu.Combo(lang.Lang("configLabel"), safe.Slice(_configs, int(_selectedConfig)), _configs, &_selectedConfig)),
 						giu.Style().SetDisabled(_disabled).To(giu.InputText(&data.Flag.Config).Hint(lang.Lang("defaultConfigPath")).Label(lang.Lang("configCustomLabel"))),
+						giu.Style().SetDisabled(_disabled).To(giu.Checkbox(lang.Lang("unroutedLabel"), &data.Flag.ListUnrouted)),
+						giu.Style().SetDisabled(_disabled).To(giu.Checkbox(lang.Lang("strictLabel"), &data.Flag.Strict)),
 					},
 					giu.Layout{
 						giu.Separator(),
@@ -192,5 +196,6 @@ func window() {
 					giu.Label(_buf.String()),
 				),
 			}),
+		passwordPopup(),
 	)
 }
@@ -114,6 +114,11 @@ func (search PathSearch) CleanWithError(info PathInfo, errCh chan<- error) {
 	filesystem.DeleteEmptyDirectories(target, errCh)
 }
 
+// IsExcluded returns true if name is protected from cleaning by the exclusions of info.
+func (search PathSearch) IsExcluded(name string, info PathInfo) bool {
+	return skip(name, search, info)
+}
+
 // Check if the file name should be excluded.
 func skip(name string, search PathSearch, info PathInfo) bool {
 	normalized := strings.Split(filesystem.Normalize(name), "/")
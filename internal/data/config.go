@@ -20,17 +20,29 @@ package data
 
 import (
 	"encoding/json"
+	"maps"
 	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/hkmh223/pd2mm/common/filesystem"
+	"github.com/hkmh223/pd2mm/common/logger"
+	"github.com/hkmh223/pd2mm/common/pattern"
 	"github.com/hkmh223/pd2mm/common/util"
-	"github.com/tidwall/jsonc"
+	"github.com/hkmh223/pd2mm/internal/lang"
 )
 
 var FileTypes = []string{".jsonc", ".json"} //nolint:gochecknoglobals // reason: file types are needed across packages.
 
 type Config struct {
-	Mods []PathSearch `json:"mods"`
+	// Schema references the JSON Schema of the config, so editors can complete and check it.
+	Schema string `json:"$schema,omitempty"` //nolint:tagliatelle // reason: editors look for the $schema key.
+
+	// Version is the ConfigVersion the config was written for, older configs are migrated when read.
+	Version int          `json:"version"`
+	Mods    []PathSearch `json:"mods"`
 }
 
 type PathSearch struct {
@@ -43,6 +55,16 @@ type PathSearch struct {
 	Expects []Expect     `json:"expects"`
 	Copy    []PathCopy   `json:"copy"`
 	Rename  []PathRename `json:"rename"`
+	Backup  []string     `json:"backup"`
+
+	// Junk patterns are added to the default junk patterns, matching files are never copied.
+	Junk []string `json:"junk"`
+
+	// Passwords of encrypted archives, keyed by the file name of the archive or a pattern matching it.
+	Passwords map[string]string `json:"passwords"`
+
+	// Disabled mods are extracted but not deployed, by identity or a pattern matching it.
+	Disabled []string `json:"disabled"`
 }
 
 type PathInfo struct {
@@ -73,21 +95,71 @@ type Expect struct {
 	Base      int    `json:"base"`
 }
 
-// Read reads the config file at path and returns a Config.
+// Read reads the config file at path and returns a Config. Errors parsing the file are a Diagnostic with
+// the line and column they were found at.
 func Read(path string) (Config, error) {
 	data, err := filesystem.ReadFile(path)
 	if err != nil {
 		return Config{}, err
 	}
 
+	migrated, version, err := Migrate(data)
+	if err != nil {
+		return Config{}, parseError(path, data, err)
+	}
+
+	if version < ConfigVersion {
+		logger.SharedLogger.Warn(lang.Lang("configVersionNotify"), "path", path, "version", version, "current", ConfigVersion)
+	}
+
+	fields, err := UnknownFields(migrated)
+	if err != nil {
+		return Config{}, err
+	}
+
+	for _, field := range fields {
+		logger.SharedLogger.Warn(lang.Lang("unknownFieldNotify"), "path", path, "field", field)
+	}
+
 	c := Config{} //nolint:exhaustruct // reason: umarshalling data into struct.
-	if err := json.Unmarshal(jsonc.ToJSON(data), &c); err != nil {
+	if err := json.Unmarshal(migrated, &c); err != nil {
+		return Config{}, parseError(path, data, err)
+	}
+
+	if err := c.compilePatterns(); err != nil {
 		return Config{}, err
 	}
 
 	return c, nil
 }
 
+// compilePatterns returns an error for the first rule path that is an invalid glob or regular expression.
+func (c Config) compilePatterns() error {
+	for _, search := range c.Mods {
+		paths := slices.Clone(search.Exclude)
+
+		for _, include := range search.Include {
+			paths = append(paths, include.Path)
+		}
+
+		for _, expect := range search.Expects {
+			paths = append(paths, expect.Path)
+		}
+
+		for _, rename := range search.Rename {
+			paths = append(paths, rename.Path, rename.From)
+		}
+
+		for _, raw := range paths {
+			if _, err := pattern.Compile(raw); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 // Write writes the config file at path.
 func Write(path string, config Config) error {
 	data, err := json.MarshalIndent(config, "", "    ")
@@ -115,17 +187,61 @@ func (search PathSearch) FormatSlice(slice []string) []string {
 
 // Replace keywords with relevant PathSearch settings.
 func (search PathSearch) FormatString(str string) string {
-	return util.Format(str, map[string]string{
+	return util.Format(str, search.keywords())
+}
+
+// Replace keywords with relevant PathSearch settings, returns false if a keyword in str is not set.
+func (search PathSearch) FormatPath(str string) (string, bool) {
+	for keyword, value := range search.keywords() {
+		if value == "" && strings.Contains(str, keyword) {
+			return "", false
+		}
+	}
+
+	return search.FormatString(str), true
+}
+
+// Password returns the password of the archive at path. A key equal to the file name is preferred,
+// otherwise the first matching pattern in sorted order is used.
+func (search PathSearch) Password(path string) (string, bool) {
+	name := filepath.Base(path)
+
+	if password, ok := search.Passwords[name]; ok {
+		return password, true
+	}
+
+	for _, pattern := range slices.Sorted(maps.Keys(search.Passwords)) {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return search.Passwords[pattern], true
+		}
+	}
+
+	return "", false
+}
+
+// IsDisabled returns true if the mod with the given identity, such as 'Archive' or 'Archive/Folder', is disabled.
+func (search PathSearch) IsDisabled(id string) bool {
+	return slices.ContainsFunc(search.Disabled, func(pattern string) bool {
+		ok, _ := path.Match(pattern, id)
+		return pattern == id || ok
+	})
+}
+
+// Keywords and the PathSearch settings they are replaced with.
+func (search PathSearch) keywords() map[string]string {
+	return map[string]string{
 		"{path}":    search.Mods,
 		"{output}":  search.Output.Path,
 		"{extract}": search.Extract.Path,
 		"{export}":  search.Export.Path,
-	})
+	}
 }
 
 //nolint:funlen // reason: setting the default config
 func Default() Config {
 	return Config{
+		Schema:  SchemaReference,
+		Version: ConfigVersion,
 		Mods: []PathSearch{
 			{
 				Mods: "pd2mm/pd2/mods",
@@ -174,6 +290,13 @@ func Default() Config {
 				},
 				Copy:   []PathCopy{},
 				Rename: []PathRename{},
+				Backup: []string{
+					"{export}/saves",
+					"{output}/saves",
+				},
+				Junk:      []string{},
+				Passwords: map[string]string{},
+				Disabled:  []string{},
 			},
 			{
 				Mods: "pd2mm/pd2/mod_overrides",
@@ -203,8 +326,12 @@ func Default() Config {
 					{Path: "soundbanks", Require: "", Exclusive: false, Base: 0},
 					{Path: "fonts", Require: "", Exclusive: false, Base: 0},
 				},
-				Copy:   []PathCopy{},
-				Rename: []PathRename{},
+				Copy:      []PathCopy{},
+				Rename:    []PathRename{},
+				Backup:    []string{},
+				Junk:      []string{},
+				Passwords: map[string]string{},
+				Disabled:  []string{},
 			},
 			{
 				Mods: "pd2mm/pd2/mod_overrides",
@@ -230,8 +357,12 @@ func Default() Config {
 						Base:      0,
 					},
 				},
-				Copy:   []PathCopy{},
-				Rename: []PathRename{},
+				Copy:      []PathCopy{},
+				Rename:    []PathRename{},
+				Backup:    []string{},
+				Junk:      []string{},
+				Passwords: map[string]string{},
+				Disabled:  []string{},
 			},
 		},
 	}
@@ -21,6 +21,7 @@ package data
 import (
 	"flag"
 
+	"github.com/hkmh223/pd2mm/common/archive"
 	"github.com/hkmh223/pd2mm/common/filesystem"
 	"github.com/hkmh223/pd2mm/common/logger"
 	"github.com/hkmh223/pd2mm/internal/lang"
@@ -35,6 +36,21 @@ type Flags struct {
 	CleanExtract bool
 	CleanExport  bool
 	CleanOutput  bool
+
+	Snapshot          bool
+	SnapshotRetention int
+	BackupRetention   int
+	LuaCheck          bool
+	BundleDB          string
+
+	ExtractMaxSize    int
+	ExtractMaxRatio   int
+	ExtractMaxEntries int
+	NamePolicy        string
+	AutoFix           bool
+	ListUnrouted      bool
+	Strict            bool
+	Trace             bool
 }
 
 var (
@@ -48,9 +64,35 @@ var (
 		CleanExtract: false,
 		CleanExport:  false,
 		CleanOutput:  false,
+
+		Snapshot:          false,
+		SnapshotRetention: 5,  //nolint:mnd // reason: default number of snapshots to keep.
+		BackupRetention:   10, //nolint:mnd // reason: default number of backups to keep.
+		LuaCheck:          false,
+		BundleDB:          "",
+
+		ExtractMaxSize:    4096,   //nolint:mnd // reason: default maximum size of an archive in MiB.
+		ExtractMaxRatio:   200,    //nolint:mnd // reason: default maximum compression ratio.
+		ExtractMaxEntries: 100000, //nolint:mnd // reason: default maximum number of entries.
+		NamePolicy:        filesystem.NamePolicyRename,
+		AutoFix:           false,
+		ListUnrouted:      true,
+		Strict:            false,
+		Trace:             false,
 	}
 )
 
+// ExtractLimits returns the limits every archive is extracted with.
+func (f Flags) ExtractLimits() archive.Limits {
+	const mebibyte = 1 << 20
+
+	return archive.Limits{
+		MaxSize:    uint64(max(f.ExtractMaxSize, 0)) * mebibyte,
+		MaxRatio:   uint64(max(f.ExtractMaxRatio, 0)),
+		MaxEntries: max(f.ExtractMaxEntries, 0),
+	}
+}
+
 // NewFlags creates a new Flags instance.
 func NewFlags() *Flags {
 	return &_defaults
@@ -59,6 +101,19 @@ func NewFlags() *Flags {
 func SetupFlags() {
 	flag.BoolVar(&Flag.Version, "version", _defaults.Version, lang.Lang("versionUsage"))
 	flag.StringVar(&Flag.Config, "config", _defaults.Config, lang.Lang("configUsage"))
+	flag.BoolVar(&Flag.Snapshot, "snapshot", _defaults.Snapshot, lang.Lang("snapshotUsage"))
+	flag.IntVar(&Flag.SnapshotRetention, "snapshot-retention", _defaults.SnapshotRetention, lang.Lang("snapshotRetentionUsage"))
+	flag.IntVar(&Flag.BackupRetention, "backup-retention", _defaults.BackupRetention, lang.Lang("backupRetentionUsage"))
+	flag.BoolVar(&Flag.LuaCheck, "lua-check", _defaults.LuaCheck, lang.Lang("luaCheckUsage"))
+	flag.StringVar(&Flag.BundleDB, "bundle-db", _defaults.BundleDB, lang.Lang("bundleDBUsage"))
+	flag.IntVar(&Flag.ExtractMaxSize, "extract-max-size", _defaults.ExtractMaxSize, lang.Lang("extractMaxSizeUsage"))
+	flag.IntVar(&Flag.ExtractMaxRatio, "extract-max-ratio", _defaults.ExtractMaxRatio, lang.Lang("extractMaxRatioUsage"))
+	flag.IntVar(&Flag.ExtractMaxEntries, "extract-max-entries", _defaults.ExtractMaxEntries, lang.Lang("extractMaxEntriesUsage"))
+	flag.StringVar(&Flag.NamePolicy, "name-policy", _defaults.NamePolicy, lang.Lang("namePolicyUsage"))
+	flag.BoolVar(&Flag.AutoFix, "auto-fix", _defaults.AutoFix, lang.Lang("autoFixUsage"))
+	flag.BoolVar(&Flag.ListUnrouted, "list-unrouted", _defaults.ListUnrouted, lang.Lang("listUnroutedUsage"))
+	flag.BoolVar(&Flag.Strict, "strict", _defaults.Strict, lang.Lang("strictUsage"))
+	flag.BoolVar(&Flag.Trace, "trace", _defaults.Trace, lang.Lang("traceUsage"))
 
 	if Flag.Lang != "" {
 		err := lang.SetLanguage(Flag.Lang)
@@ -0,0 +1,283 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package data
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/hkmh223/pd2mm/common/filesystem"
+	"github.com/tidwall/jsonc"
+)
+
+// ConfigVersion is the version of the config format, configs without a version are version 0.
+const ConfigVersion = 1
+
+var (
+	ErrConfigVersion        = errors.New("the config is newer than this version of pd2mm supports")
+	ErrInvalidConfigVersion = errors.New("the config version must be a whole number")
+)
+
+// migrations returns the migration of every old config version, the migration at index i turns version i into i+1.
+// Migrations work on the decoded JSON, so they can read fields the current Config does not have anymore.
+func migrations() []func(config map[string]any) error {
+	return []func(config map[string]any) error{
+		migrateUnversioned,
+	}
+}
+
+// Migrate migrates the jsonc content of a config to ConfigVersion.
+// It returns the migrated config as JSON and the version content had.
+func Migrate(content []byte) ([]byte, int, error) {
+	var config map[string]any
+	if err := json.Unmarshal(jsonc.ToJSON(content), &config); err != nil {
+		return nil, 0, err
+	}
+
+	version, err := configVersion(config)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if version > ConfigVersion {
+		return nil, version, fmt.Errorf("%w: version %d, supported %d", ErrConfigVersion, version, ConfigVersion)
+	}
+
+	for _, migrate := range migrations()[version:] {
+		if err := migrate(config); err != nil {
+			return nil, version, err
+		}
+	}
+
+	config["version"] = ConfigVersion
+
+	migrated, err := json.Marshal(config)
+
+	return migrated, version, err
+}
+
+// MigrateFile rewrites the config at path in the current version and returns the version it had and the unknown
+// fields that were left out. The original file is kept next to it with the old version in its name, since comments
+// and unknown fields are lost. Files that are already current are not touched.
+func MigrateFile(path string) (int, []string, error) {
+	content, err := filesystem.ReadFile(path)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	migrated, version, err := Migrate(content)
+	if err != nil || version == ConfigVersion {
+		return version, nil, err
+	}
+
+	fields, err := UnknownFields(migrated)
+	if err != nil {
+		return version, nil, err
+	}
+
+	config := Config{} //nolint:exhaustruct // reason: umarshalling data into struct.
+	if err := json.Unmarshal(migrated, &config); err != nil {
+		return version, fields, err
+	}
+
+	if err := filesystem.WriteFile(fmt.Sprintf("%s.v%d.bak", path, version), content, 0o644); err != nil { //nolint:mnd // reason: file permission.
+		return version, fields, err
+	}
+
+	return version, fields, Write(path, normalize(config))
+}
+
+// normalize fills the lists and maps a migrated config left out with empty values, so the written file shows
+// them instead of null, and references the schema like generated configs do.
+func normalize(config Config) Config {
+	if config.Schema == "" {
+		config.Schema = SchemaReference
+	}
+
+	if config.Mods == nil {
+		config.Mods = []PathSearch{}
+	}
+
+	for index := range config.Mods {
+		search := &config.Mods[index]
+
+		for _, info := range []*PathInfo{&search.Output, &search.Extract, &search.Export} {
+			info.ExcludeClean = empty(info.ExcludeClean)
+		}
+
+		search.Include = empty(search.Include)
+		search.Exclude = empty(search.Exclude)
+		search.Expects = empty(search.Expects)
+		search.Copy = empty(search.Copy)
+		search.Rename = empty(search.Rename)
+		search.Backup = empty(search.Backup)
+		search.Junk = empty(search.Junk)
+		search.Disabled = empty(search.Disabled)
+
+		if search.Passwords == nil {
+			search.Passwords = map[string]string{}
+		}
+	}
+
+	return config
+}
+
+func empty[T any](slice []T) []T {
+	if slice == nil {
+		return []T{}
+	}
+
+	return slice
+}
+
+// UnknownFields returns the path of every field of the JSON content that Config does not have, such as 'mods[0].expect'.
+func UnknownFields(content []byte) ([]string, error) {
+	var value any
+	if err := json.Unmarshal(content, &value); err != nil {
+		return nil, err
+	}
+
+	var fields []string
+
+	unknownFields(reflect.TypeFor[Config](), value, "", &fields)
+	slices.Sort(fields)
+
+	return fields, nil
+}
+
+func unknownFields(kind reflect.Type, value any, prefix string, fields *[]string) {
+	switch kind.Kind() { //nolint:exhaustive // reason: other kinds have no fields.
+	case reflect.Pointer:
+		unknownFields(kind.Elem(), value, prefix, fields)
+	case reflect.Struct:
+		object, ok := value.(map[string]any)
+		if !ok {
+			return
+		}
+
+		for key, item := range object {
+			field, ok := jsonField(kind, key)
+			if !ok {
+				*fields = append(*fields, fieldPath(prefix, key))
+				continue
+			}
+
+			unknownFields(field.Type, item, fieldPath(prefix, key), fields)
+		}
+	case reflect.Slice, reflect.Array:
+		items, _ := value.([]any)
+
+		for index, item := range items {
+			unknownFields(kind.Elem(), item, fmt.Sprintf("%s[%d]", prefix, index), fields)
+		}
+	case reflect.Map:
+		object, _ := value.(map[string]any)
+
+		for key, item := range object {
+			unknownFields(kind.Elem(), item, fieldPath(prefix, key), fields)
+		}
+	}
+}
+
+// jsonField returns the field of kind that encoding/json decodes key into, which prefers an exact match of the
+// json name but accepts any case.
+func jsonField(kind reflect.Type, key string) (reflect.StructField, bool) {
+	var folded *reflect.StructField
+
+	for index := range kind.NumField() {
+		field := kind.Field(index)
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		if name == key {
+			return field, true
+		}
+
+		if folded == nil && strings.EqualFold(name, key) {
+			folded = &field
+		}
+	}
+
+	if folded == nil {
+		return reflect.StructField{}, false //nolint:exhaustruct // reason: no field.
+	}
+
+	return *folded, true
+}
+
+func fieldPath(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+
+	return prefix + "." + key
+}
+
+func configVersion(config map[string]any) (int, error) {
+	raw, ok := config["version"]
+	if !ok {
+		return 0, nil
+	}
+
+	number, ok := raw.(float64)
+	if !ok || number < 0 || number != math.Trunc(number) || number > math.MaxInt32 {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidConfigVersion, raw)
+	}
+
+	return int(number), nil
+}
+
+// migrateUnversioned adds the settings that were added before configs had a version, so migrated files show them.
+func migrateUnversioned(config map[string]any) error {
+	mods, _ := config["mods"].([]any)
+
+	for _, entry := range mods {
+		search, ok := entry.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		defaults := map[string]any{
+			"junk":      []any{},
+			"passwords": map[string]any{},
+			"disabled":  []any{},
+			"backup":    []any{"{export}/saves", "{output}/saves"},
+		}
+
+		for key, value := range defaults {
+			if _, ok := search[key]; !ok {
+				search[key] = value
+			}
+		}
+	}
+
+	return nil
+}
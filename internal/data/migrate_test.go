@@ -0,0 +1,103 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package data_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hkmh223/pd2mm/internal/data"
+)
+
+func TestMigrate(t *testing.T) {
+	t.Parallel()
+
+	unversioned := `{
+		// comments are allowed
+		"mods": [{"mods": "pd2mm/pd2/mods", "expect": [], "output": {"path": "out", "clean": []}}],
+	}`
+
+	migrated, version, err := data.Migrate([]byte(unversioned))
+	if err != nil || version != 0 {
+		t.Fatalf("unexpected migration: %d %v", version, err)
+	}
+
+	config := data.Config{} //nolint:exhaustruct // reason: umarshalling data into struct.
+	if err := json.Unmarshal(migrated, &config); err != nil {
+		t.Fatal(err)
+	}
+
+	if config.Version != data.ConfigVersion || config.Mods[0].Junk == nil || config.Mods[0].Disabled == nil {
+		t.Fatalf("unexpected config: %+v", config)
+	}
+
+	if !slices.Equal(config.Mods[0].Backup, []string{"{export}/saves", "{output}/saves"}) {
+		t.Fatalf("unexpected backup: %v", config.Mods[0].Backup)
+	}
+
+	fields, err := data.UnknownFields(migrated)
+	if err != nil || !slices.Equal(fields, []string{"mods[0].expect", "mods[0].output.clean"}) {
+		t.Fatalf("unexpected unknown fields: %v %v", fields, err)
+	}
+
+	if _, _, err := data.Migrate([]byte(`{"version": 99}`)); !errors.Is(err, data.ErrConfigVersion) {
+		t.Fatalf("expected a newer version to fail: %v", err)
+	}
+}
+
+func TestMigrateFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "config.jsonc")
+	if err := os.WriteFile(path, []byte(`{"mods": [{"mods": "pd2mm/pd2/mods", "output": {"path": "out"}}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	version, _, err := data.MigrateFile(path)
+	if err != nil || version != 0 {
+		t.Fatalf("unexpected migration: %d %v", version, err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(string(content), "null") {
+		t.Fatalf("migrated config has null values:\n%s", content)
+	}
+
+	config := data.Config{} //nolint:exhaustruct // reason: umarshalling data into struct.
+	if err := json.Unmarshal(content, &config); err != nil {
+		t.Fatal(err)
+	}
+
+	if config.Schema != data.SchemaReference || len(config.Mods[0].Backup) != 2 {
+		t.Fatalf("unexpected config: %+v", config)
+	}
+
+	if _, err := os.Stat(path + ".v0.bak"); err != nil {
+		t.Fatalf("expected the original config to be kept: %v", err)
+	}
+}
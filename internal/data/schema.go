@@ -0,0 +1,131 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package data
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/hkmh223/pd2mm/common/filesystem"
+	"github.com/hkmh223/pd2mm/internal/lang"
+)
+
+// SchemaReference is the $schema of generated configs, the schema is written next to them.
+const SchemaReference = "./schema.json"
+
+const schemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// Schema returns the JSON Schema of Config, generated from the types so it follows every change to them.
+// Descriptions are the language strings named after the type and field, such as 'schemaExpectBase',
+// defaults are the values of Default.
+func Schema() ([]byte, error) {
+	definitions := map[string]any{}
+
+	root := schemaObject(reflect.TypeFor[Config](), reflect.ValueOf(Default()), definitions)
+	root["$schema"] = schemaDraft
+	root["title"] = lang.Lang("schemaTitle")
+	root["definitions"] = definitions
+
+	return json.MarshalIndent(root, "", "    ")
+}
+
+// WriteSchema writes the schema to path, the file is left untouched if it is current.
+func WriteSchema(path string) error {
+	schema, err := Schema()
+	if err != nil {
+		return err
+	}
+
+	if current, err := filesystem.ReadFile(path); err == nil && bytes.Equal(current, schema) {
+		return nil
+	}
+
+	return filesystem.WriteFile(path, schema, os.ModePerm)
+}
+
+// schemaObject returns the schema of the struct kind, value holds the defaults of its fields.
+// Nested structs are added to definitions and referenced.
+func schemaObject(kind reflect.Type, value reflect.Value, definitions map[string]any) map[string]any {
+	properties := map[string]any{}
+
+	for index := range kind.NumField() {
+		field := kind.Field(index)
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+
+		property := schemaType(field.Type, definitions)
+
+		if field.Type.Kind() == reflect.Struct {
+			// Keywords next to $ref are ignored by draft 7, so the description needs allOf.
+			property = map[string]any{"allOf": []any{property}}
+		} else {
+			property["default"] = schemaDefault(value.Field(index))
+		}
+
+		property["description"] = lang.Lang("schema" + kind.Name() + field.Name)
+		properties[name] = property
+	}
+
+	return map[string]any{"type": "object", "properties": properties, "additionalProperties": false}
+}
+
+func schemaType(kind reflect.Type, definitions map[string]any) map[string]any {
+	switch kind.Kind() { //nolint:exhaustive // reason: Config only uses these kinds.
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": schemaType(kind.Elem(), definitions)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaType(kind.Elem(), definitions)}
+	case reflect.Struct:
+		if _, ok := definitions[kind.Name()]; !ok {
+			definitions[kind.Name()] = schemaObject(kind, reflect.Zero(kind), definitions)
+		}
+
+		return map[string]any{"$ref": "#/definitions/" + kind.Name()}
+	}
+
+	return map[string]any{}
+}
+
+// schemaDefault returns the default of a field, nil slices and maps are empty so editors insert them as such.
+func schemaDefault(value reflect.Value) any {
+	switch value.Kind() { //nolint:exhaustive // reason: other kinds have no nil value.
+	case reflect.Slice:
+		if value.IsNil() {
+			return []any{}
+		}
+	case reflect.Map:
+		if value.IsNil() {
+			return map[string]any{}
+		}
+	}
+
+	return value.Interface()
+}
@@ -0,0 +1,69 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package data_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hkmh223/pd2mm/internal/data"
+)
+
+type schemaObject struct {
+	Properties  map[string]map[string]any `json:"properties"`
+	Definitions map[string]schemaObject   `json:"definitions"`
+}
+
+func TestSchema(t *testing.T) {
+	t.Parallel()
+
+	content, err := data.Schema()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var schema schemaObject
+	if err := json.Unmarshal(content, &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"PathSearch", "PathInfo", "Include", "Expect", "PathCopy", "PathRename"} {
+		if _, ok := schema.Definitions[name]; !ok {
+			t.Errorf("missing definition %s", name)
+		}
+	}
+
+	objects := map[string]schemaObject{"Config": schema}
+	for name, definition := range schema.Definitions {
+		objects[name] = definition
+	}
+
+	// A field without a language string has an empty description.
+	for name, object := range objects {
+		for field, property := range object.Properties {
+			if property["description"] == "" {
+				t.Errorf("%s.%s has no description", name, field)
+			}
+		}
+	}
+
+	if _, ok := schema.Properties["$schema"]; !ok || data.Default().Schema != data.SchemaReference {
+		t.Fatal("the default config does not reference the schema")
+	}
+}
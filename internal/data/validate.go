@@ -0,0 +1,390 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package data
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hkmh223/pd2mm/common/filesystem"
+	"github.com/hkmh223/pd2mm/common/pattern"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/jsonc"
+)
+
+var (
+	ErrInvalidConfig      = errors.New("the config has problems")
+	ErrOldConfigVersion   = errors.New("the config was written for an older version, 'config migrate' updates it")
+	ErrUnknownField       = errors.New("unknown field")
+	ErrEmptyMods          = errors.New("the mods path is empty")
+	ErrUnknownPlaceholder = errors.New("unknown placeholder")
+	ErrOverlappingPaths   = errors.New("directories overlap")
+	ErrEmptyRule          = errors.New("the rule path is empty")
+	ErrNeverMatches       = errors.New("the rule can never match")
+	ErrBaseTooLarge       = errors.New("base is larger than the depth of the path")
+	ErrNegativeBase       = errors.New("base can not be negative")
+)
+
+var _placeholder = regexp.MustCompile(`\{[A-Za-z_]+\}`) //nolint:gochecknoglobals // reason: compiled once.
+
+// Diagnostic is a problem found in a config file. Line and Column point at the value of Field in the original text,
+// they are zero if the problem has no position.
+type Diagnostic struct {
+	File    string
+	Line    int
+	Column  int
+	Field   string
+	Warning bool
+	Err     error
+}
+
+func (d Diagnostic) Error() string {
+	if d.Field == "" {
+		return d.Position() + ": " + d.Err.Error()
+	}
+
+	return d.Position() + ": " + d.Field + ": " + d.Err.Error()
+}
+
+func (d Diagnostic) Unwrap() error {
+	return d.Err
+}
+
+// Position returns the file, line and column of the diagnostic as 'file:line:column'.
+func (d Diagnostic) Position() string {
+	if d.Line == 0 {
+		return d.File
+	}
+
+	return fmt.Sprintf("%s:%d:%d", d.File, d.Line, d.Column)
+}
+
+// Validate reads the config at path and returns every problem found in it.
+func Validate(path string) ([]Diagnostic, error) {
+	content, err := filesystem.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return ValidateContent(path, content), nil
+}
+
+// ValidateContent returns every problem of the jsonc content of the config file. A config that can not be parsed
+// only returns the parse error.
+func ValidateContent(file string, content []byte) []Diagnostic {
+	migrated, version, err := Migrate(content)
+	if err != nil {
+		return []Diagnostic{parseError(file, content, err)}
+	}
+
+	config := Config{} //nolint:exhaustruct // reason: umarshalling data into struct.
+	if err := json.Unmarshal(migrated, &config); err != nil {
+		return []Diagnostic{parseError(file, content, err)}
+	}
+
+	var diagnostics []Diagnostic
+
+	if version < ConfigVersion {
+		diagnostics = append(diagnostics, warning("version", fmt.Errorf("%w: version %d, current %d", ErrOldConfigVersion, version, ConfigVersion)))
+	}
+
+	fields, err := UnknownFields(migrated)
+	if err != nil {
+		return []Diagnostic{parseError(file, content, err)}
+	}
+
+	for _, field := range fields {
+		diagnostics = append(diagnostics, warning(field, ErrUnknownField))
+	}
+
+	for index, search := range config.Mods {
+		diagnostics = append(diagnostics, search.validate(fmt.Sprintf("mods[%d]", index))...)
+	}
+
+	for index := range diagnostics {
+		diagnostics[index].File = file
+		diagnostics[index].Line, diagnostics[index].Column = locate(content, diagnostics[index].Field)
+	}
+
+	return diagnostics
+}
+
+// parseError returns err as a Diagnostic at the position in content it was found at.
+func parseError(file string, content []byte, err error) Diagnostic {
+	diagnostic := Diagnostic{File: file, Line: 0, Column: 0, Field: "", Warning: false, Err: err}
+
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+	)
+
+	switch {
+	case errors.As(err, &syntaxErr):
+		// jsonc.ToJSON keeps every byte at its offset, so offsets into the JSON are offsets into content.
+		diagnostic.Line, diagnostic.Column = lineColumn(content, int(syntaxErr.Offset)-1)
+	case errors.As(err, &typeErr):
+		diagnostic.Field = displayField(typeErr.Field)
+		diagnostic.Line, diagnostic.Column = locate(content, diagnostic.Field)
+	case errors.Is(err, ErrConfigVersion), errors.Is(err, ErrInvalidConfigVersion):
+		diagnostic.Field = "version"
+		diagnostic.Line, diagnostic.Column = locate(content, diagnostic.Field)
+	}
+
+	return diagnostic
+}
+
+// locate returns the line and column of the value of field, such as 'mods[0].expects[1].base', in content.
+func locate(content []byte, field string) (int, int) {
+	if field == "" {
+		return 0, 0
+	}
+
+	result := gjson.GetBytes(jsonc.ToJSON(content), strings.NewReplacer("[", ".", "]", "").Replace(field))
+	if !result.Exists() {
+		return 0, 0
+	}
+
+	return lineColumn(content, result.Index)
+}
+
+// lineColumn returns the line and column of offset in content, both starting at one.
+func lineColumn(content []byte, offset int) (int, int) {
+	offset = max(0, min(offset, len(content)))
+	before := content[:offset]
+	line := strings.Count(string(before), "\n") + 1
+
+	if index := strings.LastIndexByte(string(before), '\n'); index >= 0 {
+		before = before[index+1:]
+	}
+
+	return line, utf8.RuneCount(before) + 1
+}
+
+// displayField turns a field path of encoding/json, such as 'mods.0.expects', into 'mods[0].expects'.
+func displayField(field string) string {
+	var result string
+
+	for _, part := range strings.Split(field, ".") {
+		if _, err := strconv.Atoi(part); err == nil {
+			result += "[" + part + "]"
+		} else {
+			result = fieldPath(result, part)
+		}
+	}
+
+	return result
+}
+
+func warning(field string, err error) Diagnostic {
+	return Diagnostic{File: "", Line: 0, Column: 0, Field: field, Warning: true, Err: err}
+}
+
+func problem(field string, err error) Diagnostic {
+	return Diagnostic{File: "", Line: 0, Column: 0, Field: field, Warning: false, Err: err}
+}
+
+// fieldValue is a path of a PathSearch setting, rule paths are matched against the files of mods.
+type fieldValue struct {
+	field string
+	value string
+	rule  bool
+}
+
+// validate returns the problems of the PathSearch, fields are prefixed with prefix.
+func (search PathSearch) validate(prefix string) []Diagnostic {
+	var diagnostics []Diagnostic
+
+	if strings.TrimSpace(search.Mods) == "" {
+		diagnostics = append(diagnostics, problem(prefix+".mods", ErrEmptyMods))
+	}
+
+	for _, value := range search.fieldValues(prefix) {
+		diagnostics = append(diagnostics, search.validateValue(value)...)
+	}
+
+	diagnostics = append(diagnostics, search.validateDirectories(prefix)...)
+
+	return append(diagnostics, search.validateExpects(prefix)...)
+}
+
+// directories returns the directories of the PathSearch.
+func (search PathSearch) directories(prefix string) []fieldValue {
+	return []fieldValue{
+		{field: prefix + ".mods", value: search.Mods, rule: false},
+		{field: prefix + ".output.path", value: search.Output.Path, rule: false},
+		{field: prefix + ".extract.path", value: search.Extract.Path, rule: false},
+		{field: prefix + ".export.path", value: search.Export.Path, rule: false},
+	}
+}
+
+// fieldValues returns every path of the PathSearch that may use placeholders.
+func (search PathSearch) fieldValues(prefix string) []fieldValue {
+	values := search.directories(prefix)
+
+	add := func(rule bool, field, value string) {
+		values = append(values, fieldValue{field: field, value: value, rule: rule})
+	}
+
+	addAll := func(rule bool, field string, items []string) {
+		for index, item := range items {
+			add(rule, fmt.Sprintf("%s[%d]", field, index), item)
+		}
+	}
+
+	addAll(false, prefix+".output.excludeClean", search.Output.ExcludeClean)
+	addAll(false, prefix+".extract.excludeClean", search.Extract.ExcludeClean)
+	addAll(false, prefix+".export.excludeClean", search.Export.ExcludeClean)
+	addAll(true, prefix+".exclude", search.Exclude)
+	addAll(false, prefix+".backup", search.Backup)
+
+	for index, include := range search.Include {
+		add(true, fmt.Sprintf("%s.include[%d].path", prefix, index), include.Path)
+		add(false, fmt.Sprintf("%s.include[%d].to", prefix, index), include.To)
+	}
+
+	for index, expect := range search.Expects {
+		add(true, fmt.Sprintf("%s.expects[%d].path", prefix, index), expect.Path)
+		add(false, fmt.Sprintf("%s.expects[%d].require", prefix, index), expect.Require)
+	}
+
+	for index, copy := range search.Copy {
+		add(false, fmt.Sprintf("%s.copy[%d].from", prefix, index), copy.From)
+		add(false, fmt.Sprintf("%s.copy[%d].to", prefix, index), copy.To)
+	}
+
+	for index, rename := range search.Rename {
+		add(true, fmt.Sprintf("%s.rename[%d].path", prefix, index), rename.Path)
+		add(true, fmt.Sprintf("%s.rename[%d].from", prefix, index), rename.From)
+		add(false, fmt.Sprintf("%s.rename[%d].to", prefix, index), rename.To)
+	}
+
+	return values
+}
+
+// validateValue checks the placeholders of a path, rule paths must also be valid patterns that can match.
+func (search PathSearch) validateValue(value fieldValue) []Diagnostic {
+	if value.rule {
+		if value.value == "" {
+			return []Diagnostic{problem(value.field, ErrEmptyRule)}
+		}
+
+		if _, err := pattern.Compile(value.value); err != nil {
+			return []Diagnostic{problem(value.field, err)}
+		}
+	}
+
+	var diagnostics []Diagnostic
+
+	keywords := search.keywords()
+
+	for _, placeholder := range _placeholder.FindAllString(value.value, -1) {
+		keyword, known := keywords[placeholder]
+
+		switch {
+		case !known:
+			names := slices.Sorted(maps.Keys(keywords))
+			diagnostics = append(diagnostics, problem(value.field, fmt.Errorf("%w '%s', expected one of: %s", ErrUnknownPlaceholder, placeholder, strings.Join(names, ", "))))
+		case value.rule && !pattern.IsPlain(value.value):
+			diagnostics = append(diagnostics, problem(value.field, fmt.Errorf("%w: '%s' is not replaced in glob or regex patterns", ErrNeverMatches, placeholder)))
+		case value.rule && keyword == "":
+			diagnostics = append(diagnostics, problem(value.field, fmt.Errorf("%w: '%s' is empty", ErrNeverMatches, placeholder)))
+		}
+	}
+
+	return diagnostics
+}
+
+// validateDirectories returns a problem for every directory that is equal to or inside another one.
+// Cleaning or writing one of them would change the other.
+func (search PathSearch) validateDirectories(prefix string) []Diagnostic {
+	var (
+		diagnostics []Diagnostic
+		resolved    []fieldValue
+	)
+
+	for _, directory := range search.directories(prefix) {
+		if directory.value == "" || _placeholder.MatchString(search.FormatString(directory.value)) {
+			continue
+		}
+
+		abs, err := filepath.Abs(search.FormatString(directory.value))
+		if err != nil {
+			continue
+		}
+
+		for _, other := range resolved {
+			if within(other.value, abs) || within(abs, other.value) {
+				diagnostics = append(diagnostics, problem(directory.field, fmt.Errorf("%w: %s and %s", ErrOverlappingPaths, other.field, directory.field)))
+			}
+		}
+
+		resolved = append(resolved, fieldValue{field: directory.field, value: abs, rule: false})
+	}
+
+	return diagnostics
+}
+
+// validateExpects returns a problem for every expected path that can never match and for base values larger than
+// the require path and the copied folder.
+func (search PathSearch) validateExpects(prefix string) []Diagnostic {
+	var diagnostics []Diagnostic
+
+	for index, expect := range search.Expects {
+		field := fmt.Sprintf("%s.expects[%d]", prefix, index)
+
+		if depth := len(strings.FieldsFunc(filesystem.Normalize(expect.Require), func(r rune) bool { return r == '/' })) + 1; expect.Base > depth {
+			diagnostics = append(diagnostics, problem(field+".base", fmt.Errorf("%w: base %d, depth %d", ErrBaseTooLarge, expect.Base, depth)))
+		} else if expect.Base < 0 {
+			diagnostics = append(diagnostics, problem(field+".base", ErrNegativeBase))
+		}
+
+		if expect.Path == "" {
+			continue
+		}
+
+		// The first expected path that matches is used, so a later one with the same path is never reached.
+		if earlier := slices.IndexFunc(search.Expects[:index], func(other Expect) bool { return other.Path == expect.Path }); earlier >= 0 {
+			diagnostics = append(diagnostics, problem(field+".path", fmt.Errorf("%w: %s.expects[%d] has the same path and is checked first", ErrNeverMatches, prefix, earlier)))
+		}
+
+		// Excludes are checked before expected paths, an equal exclude removes every file the expected path matches.
+		// Plain paths with several segments or an extension match files that do not contain them, so only single
+		// segments are certain.
+		certain := !pattern.IsPlain(expect.Path) || !strings.ContainsAny(expect.Path, "/\\") && !strings.HasPrefix(expect.Path, ".")
+
+		if excluded := slices.Index(search.Exclude, expect.Path); certain && excluded >= 0 {
+			diagnostics = append(diagnostics, problem(field+".path", fmt.Errorf("%w: %s.exclude[%d] removes every file it matches", ErrNeverMatches, prefix, excluded)))
+		}
+	}
+
+	return diagnostics
+}
+
+// within returns true if child is parent or inside of it.
+func within(parent, child string) bool {
+	return child == parent || strings.HasPrefix(child, parent+string(filepath.Separator))
+}
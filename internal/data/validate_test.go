@@ -0,0 +1,69 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package data_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hkmh223/pd2mm/internal/data"
+)
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	broken := "{\n\t// comment\n\t\"version\": 1,\n\t\"mods\": [{\"mods\": \"a\" \"output\": {}}],\n}"
+
+	diagnostics := data.ValidateContent("pd2.json", []byte(broken))
+	if len(diagnostics) != 1 || diagnostics[0].Line != 4 || diagnostics[0].Column != 24 {
+		t.Fatalf("unexpected parse diagnostics: %v", diagnostics)
+	}
+
+	config := `{
+		"version": 1,
+		"mods": [{
+			"mods": "",
+			"output": {"path": "out"},
+			"extract": {"path": "out/extract"},
+			"exclude": ["{export}/mods", "mod.txt"],
+			"expects": [{"path": "mod.txt"}, {"path": "main.xml", "require": "{mods}", "base": 3}, {"path": "main.xml"}],
+		}],
+	}`
+
+	expected := map[string]error{
+		"mods[0].mods":               data.ErrEmptyMods,
+		"mods[0].extract.path":       data.ErrOverlappingPaths,
+		"mods[0].exclude[0]":         data.ErrNeverMatches,
+		"mods[0].expects[0].path":    data.ErrNeverMatches,
+		"mods[0].expects[1].base":    data.ErrBaseTooLarge,
+		"mods[0].expects[1].require": data.ErrUnknownPlaceholder,
+		"mods[0].expects[2].path":    data.ErrNeverMatches,
+	}
+
+	diagnostics = data.ValidateContent("pd2.json", []byte(config))
+	if len(diagnostics) != len(expected) {
+		t.Fatalf("unexpected diagnostics: %v", diagnostics)
+	}
+
+	for _, diagnostic := range diagnostics {
+		if !errors.Is(diagnostic, expected[diagnostic.Field]) || diagnostic.Line == 0 {
+			t.Errorf("unexpected diagnostic: %v", diagnostic)
+		}
+	}
+}
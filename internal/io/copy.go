@@ -20,20 +20,116 @@ package io
 
 import (
 	"os"
+	"path/filepath"
 
 	"github.com/hkmh223/pd2mm/common/filesystem"
 	"github.com/hkmh223/pd2mm/common/logger"
+	"github.com/hkmh223/pd2mm/internal/data"
+	"github.com/hkmh223/pd2mm/internal/lang"
 	"github.com/otiai10/copy"
 )
 
+// nameCheck applies the name policy to the files of one copy and logs names that differ only by case.
+type nameCheck struct {
+	root   string
+	policy string
+	cases  *filesystem.CaseCollisions
+}
+
 // CopyFile copies a file from the source to the destination.
-// It skips files that are not allowed to be copied by pathCheck.
+// It skips files that are not allowed to be copied by pathCheck, and handles names that are invalid on Windows
+// according to the name policy.
 //
 //nolint:lll // reason: struct function increases size.
 func CopyFile(src, dest string) error {
-	return filesystem.Copy(src, dest, copy.Options{Skip: func(_ os.FileInfo, src, dest string) (bool, error) { //nolint:exhaustruct // reason: not all options are needed.
-		return PathCheck(src, dest), nil
-	}, PermissionControl: copy.AddPermission(0o666)})
+	_, err := CopyFiltered(src, dest, nil)
+	return err
+}
+
+// CopyFiltered copies like CopyFile, leaving out every path filter returns true for. Paths are passed to filter
+// relative to src, or as the file name if src is a file. It returns the number of files left out.
+//
+//nolint:lll // reason: struct function increases size.
+func CopyFiltered(src, dest string, filter func(path string) bool) (int, error) {
+	if filter != nil && filter(filepath.Base(src)) {
+		info, err := os.Stat(src)
+		if err != nil {
+			return 0, err
+		}
+
+		return countFiles(info, src), nil
+	}
+
+	filtered := 0
+	names := nameCheck{root: dest, policy: data.Flag.NamePolicy, cases: filesystem.NewCaseCollisions()}
+
+	err := filesystem.Copy(src, dest, copy.Options{Skip: func(info os.FileInfo, path, dest string) (bool, error) { //nolint:exhaustruct // reason: not all options are needed.
+		if filter != nil && filter(relative(src, path)) {
+			filtered += countFiles(info, path)
+			return true, nil
+		}
+
+		return PathCheck(path, dest) || names.skip(path), nil
+	}, RenameDestination: names.rename, PermissionControl: copy.AddPermission(0o666)})
+
+	return filtered, err
+}
+
+// relative returns path relative to root, or its name if path is root.
+func relative(root, path string) string {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == "." {
+		return filepath.Base(path)
+	}
+
+	return rel
+}
+
+// countFiles returns 1 for a file and the number of files in it for a directory.
+func countFiles(info os.FileInfo, path string) int {
+	if !info.IsDir() {
+		return 1
+	}
+
+	return len(filesystem.GetFiles(path))
+}
+
+// skip returns true for files with names that are invalid on Windows if the policy rejects them.
+func (n nameCheck) skip(src string) bool {
+	if n.policy != filesystem.NamePolicyReject {
+		return false
+	}
+
+	if err := filesystem.CheckName(filepath.Base(src)); err != nil {
+		logger.SharedLogger.Error(lang.Lang("invalidNameNotify"), "path", src, "err", err)
+		return true
+	}
+
+	return false
+}
+
+// rename renames the last element of dest if it is invalid on Windows and the policy renames, parents are
+// already renamed when their contents are copied.
+func (n nameCheck) rename(src, dest string) (string, error) {
+	if dest == n.root {
+		return dest, nil
+	}
+
+	if err := filesystem.CheckName(filepath.Base(dest)); err != nil {
+		if n.policy == filesystem.NamePolicyRename {
+			dest = filepath.Join(filepath.Dir(dest), filesystem.SanitizeName(filepath.Base(dest)))
+		}
+
+		logger.SharedLogger.Warn(lang.Lang("invalidNameNotify"), "path", src, "destination", dest, "err", err)
+	}
+
+	if rel, err := filepath.Rel(n.root, dest); err == nil {
+		if other, ok := n.cases.Add(filepath.ToSlash(rel)); ok {
+			logger.SharedLogger.Warn(lang.Lang("caseCollisionNotify"), "path", src, "other", other)
+		}
+	}
+
+	return dest, nil
 }
 
 // PathCheck checks if a file is allowed to be copied by the given source and destination paths.
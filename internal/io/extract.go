@@ -20,57 +20,138 @@ package io
 
 import (
 	"fmt"
-	"runtime"
+	"os"
+	"path/filepath"
 
+	"github.com/hkmh223/pd2mm/common/archive"
 	"github.com/hkmh223/pd2mm/common/errors"
 	"github.com/hkmh223/pd2mm/common/filesystem"
 	"github.com/hkmh223/pd2mm/common/logger"
-	"github.com/hkmh223/pd2mm/common/sevenzip"
 	"github.com/hkmh223/pd2mm/internal/data"
 	"github.com/hkmh223/pd2mm/internal/lang"
 )
 
+// Problem is an archive that broke the extraction limits, could not be decrypted or had entries skipped. Problems are reported per archive,
+// the other archives are still extracted.
+type Problem struct {
+	Archive string
+	Err     error
+	// Rejected is true if nothing of the archive was kept.
+	Rejected bool
+}
+
 // Extract extracts the contents of an archive to a specified directory.
-func Extract(flags data.Flags, search data.PathSearch) error {
+func Extract(flags data.Flags, search data.PathSearch) ([]Problem, error) {
 	source, err := filesystem.FromCwd(search.Mods)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	destination, err := filesystem.FromCwd(search.Extract.Path)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if err := extract(flags, source, destination); err != nil {
-		return &errors.MError{Header: "Extract", Message: fmt.Sprintf("failed to extract '%s' to '%s'", source, destination), Err: err}
+	problems, err := extract(flags, search, source, destination)
+	if err != nil {
+		return problems, &errors.MError{Header: "Extract", Message: fmt.Sprintf("failed to extract '%s' to '%s'", source, destination), Err: err}
 	}
 
-	return nil
+	return problems, nil
 }
 
 // extract extracts the contents of an archive to a specified directory.
-func extract(flags data.Flags, src, dest string) error {
-	files := filesystem.GetFiles(src)
+//
+//nolint:cyclop,funlen // reason: every archive goes through the same steps.
+func extract(flags data.Flags, search data.PathSearch, src, dest string) ([]Problem, error) {
+	var problems []Problem
 
-	bin := filesystem.Combine(flags.Bin, sevenzip.LinuxName)
-	if runtime.GOOS == "windows" {
-		bin = filesystem.Combine(flags.Bin, sevenzip.WindowsName)
+	if err := filesystem.CheckNamePolicy(flags.NamePolicy); err != nil {
+		return nil, err
 	}
 
-	for _, file := range files {
-		logger.SharedLogger.Info(lang.Lang("extractNotify"), "source", file, "destination", dest)
+	extractors := Extractors(flags)
+
+	for _, file := range filesystem.GetFiles(src) {
+		if archive.IsPartial(file) {
+			logger.SharedLogger.Warn(lang.Lang("partialDownloadNotify"), "source", file)
+			continue
+		}
+
+		format, err := archive.DetectFile(file)
+		if err != nil {
+			return problems, err
+		}
+
+		extractor, ok := ExtractorFor(extractors, format)
+		if !ok {
+			logger.SharedLogger.Info(lang.Lang("notArchiveNotify"), "source", file)
+			continue
+		}
+
+		password, err := archivePassword(search, extractor, file, format)
+		if isPasswordError(err) {
+			logger.SharedLogger.Error(lang.Lang("passwordFailedNotify"), "source", file, "err", err)
+			problems = append(problems, Problem{Archive: file, Err: err, Rejected: true})
+
+			continue
+		}
+
+		if err != nil {
+			return problems, err
+		}
+
+		target := filepath.Join(dest, ArchiveName(file))
+
+		logger.SharedLogger.Info(lang.Lang("extractNotify"), "source", file, "destination", dest, "format", format)
 
-		if filesystem.Exists(bin) {
-			if _, err := sevenzip.ExtractWithBin(file, dest, bin, false); err != nil {
-				return err
+		guard, err := ExtractArchive(flags, extractors, file, dest, format, password)
+		if err != nil {
+			switch {
+			case isViolation(err):
+				logger.SharedLogger.Error(lang.Lang("unsafeArchiveNotify"), "source", file, "err", err)
+			case isPasswordError(err):
+				logger.SharedLogger.Error(lang.Lang("passwordFailedNotify"), "source", file, "err", err)
+			default:
+				return problems, fmt.Errorf("%w: %q", err, file)
 			}
-		} else {
-			if _, err := sevenzip.Extract(file, dest, false, sevenzip.ExtractionOptions{HideWindow: true, Relative: false}); err != nil {
-				return err
+
+			problems = append(problems, Problem{Archive: file, Err: err, Rejected: true})
+
+			if err := os.RemoveAll(target); err != nil {
+				return problems, err
 			}
+
+			continue
+		}
+
+		for _, err := range guard.Warnings() {
+			logger.SharedLogger.Warn(lang.Lang("entryWarningNotify"), "source", file, "err", err)
+			problems = append(problems, Problem{Archive: file, Err: err, Rejected: false})
+		}
+	}
+
+	return problems, nil
+}
+
+// archivePassword returns the password of the archive at src if it is encrypted, from the config or else the prompt.
+func archivePassword(search data.PathSearch, extractor Extractor, src string, format archive.Format) (string, error) {
+	encrypted, err := extractor.Encrypted(src, format)
+	if err != nil || !encrypted {
+		return "", err
+	}
+
+	logger.SharedLogger.Info(lang.Lang("encryptedArchiveNotify"), "source", src)
+
+	if password, ok := search.Password(src); ok {
+		return password, nil
+	}
+
+	if PasswordPrompt != nil {
+		if password, ok := PasswordPrompt(src); ok && password != "" {
+			return password, nil
 		}
 	}
 
-	return nil
+	return "", fmt.Errorf("%w: %q", ErrPasswordRequired, src)
 }
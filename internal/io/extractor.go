@@ -0,0 +1,319 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package io
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/hkmh223/pd2mm/common/archive"
+	"github.com/hkmh223/pd2mm/common/filesystem"
+	"github.com/hkmh223/pd2mm/common/logger"
+	"github.com/hkmh223/pd2mm/common/sevenzip"
+	"github.com/hkmh223/pd2mm/common/tar"
+	"github.com/hkmh223/pd2mm/common/zip"
+	"github.com/hkmh223/pd2mm/internal/data"
+	"github.com/hkmh223/pd2mm/internal/lang"
+)
+
+// ErrPasswordRequired is returned for encrypted archives without a configured or entered password.
+var ErrPasswordRequired = errors.New("the archive is encrypted, set its password in 'passwords' of the config")
+
+// PasswordPrompt asks for the password of the encrypted archive at src, it returns false if none was entered.
+// It is set by the frontends, encrypted archives without a configured password fail if it is nil.
+var PasswordPrompt func(src string) (string, bool) //nolint:gochecknoglobals // reason: set by the frontend.
+
+// Extractor extracts archives into a directory named after the archive.
+type Extractor interface {
+	// Supports returns true if the extractor can extract archives of the format.
+	Supports(format archive.Format) bool
+	// Encrypted returns true if the archive at src needs a password, without extracting anything.
+	Encrypted(src string, format archive.Format) (bool, error)
+	// Extract extracts the archive at src into dest/<archive name>, decrypting it with password.
+	// Every entry is checked by guard, which must be created for dest/<archive name>.
+	Extract(src, dest string, format archive.Format, password string, guard *archive.Guard) error
+}
+
+// NativeExtractor extracts zip, tar, tar.gz, tar.xz and tar.bz2 archives without external programs.
+// Formats are detected by signature, so an archive with the wrong extension is still extracted.
+type NativeExtractor struct{}
+
+// SevenZipExtractor extracts any archive 7-Zip supports, using Bin if it exists and 7z from PATH otherwise.
+type SevenZipExtractor struct {
+	Bin string
+}
+
+// Extractors returns the extractors in the order they are tried. 7-Zip is the fallback for every other format.
+func Extractors(flags data.Flags) []Extractor {
+	bin := filesystem.Combine(flags.Bin, sevenzip.LinuxName)
+	if runtime.GOOS == "windows" {
+		bin = filesystem.Combine(flags.Bin, sevenzip.WindowsName)
+	}
+
+	return []Extractor{NativeExtractor{}, SevenZipExtractor{Bin: bin}}
+}
+
+// ExtractorFor returns the first extractor that supports the format.
+func ExtractorFor(extractors []Extractor, format archive.Format) (Extractor, bool) {
+	for _, extractor := range extractors {
+		if extractor.Supports(format) {
+			return extractor, true
+		}
+	}
+
+	return nil, false
+}
+
+// ExtractArchive extracts the archive at src with the first extractor that supports its format. An extractor that
+// can not read a compression or encryption method hands the archive to the next one, with the files it extracted
+// removed and a new guard. The guard of the extractor that finished is returned for its warnings.
+func ExtractArchive(
+	flags data.Flags, extractors []Extractor, src, dest string, format archive.Format, password string,
+) (*archive.Guard, error) {
+	target := filepath.Join(dest, ArchiveName(src))
+
+	var err error
+
+	for _, extractor := range extractors {
+		if !extractor.Supports(format) {
+			continue
+		}
+
+		guard, guardErr := archive.NewGuard(src, target, flags.ExtractLimits())
+		if guardErr != nil {
+			return nil, guardErr
+		}
+
+		guard.SetNamePolicy(flags.NamePolicy)
+
+		if err = extractor.Extract(src, dest, format, password, guard); !zip.IsUnsupported(err) {
+			return guard, err
+		}
+
+		logger.SharedLogger.Warn(lang.Lang("unsupportedMethodNotify"), "source", src, "err", err)
+
+		if removeErr := os.RemoveAll(target); removeErr != nil {
+			return nil, removeErr
+		}
+	}
+
+	return nil, err
+}
+
+func (NativeExtractor) Supports(format archive.Format) bool {
+	return slices.Contains([]archive.Format{archive.Zip, archive.Tar, archive.TarGzip, archive.TarXz, archive.TarBzip2}, format)
+}
+
+func (NativeExtractor) Encrypted(src string, format archive.Format) (bool, error) {
+	if format != archive.Zip {
+		return false, nil
+	}
+
+	return zip.IsEncrypted(src)
+}
+
+func (NativeExtractor) Extract(src, dest string, format archive.Format, password string, guard *archive.Guard) error {
+	if format == archive.Zip {
+		opts := zip.UnzipOptions{Prefix: "", Password: password, Guard: guard, Messenger: zip.Messenger{AddedFile: func(string) {}}}
+		return zip.UnzipWithOptions(src, filepath.Join(dest, ArchiveName(src)), opts)
+	}
+
+	return tar.UntarWithGuard(src, guard)
+}
+
+func (SevenZipExtractor) Supports(format archive.Format) bool {
+	return format.IsArchive()
+}
+
+// Encrypted lists the archive without a password, archives with encrypted headers can not be listed at all.
+func (s SevenZipExtractor) Encrypted(src string, _ archive.Format) (bool, error) {
+	var (
+		entries []sevenzip.Entry
+		code    sevenzip.ErrorCode
+		err     error
+	)
+
+	opts := sevenzip.ExtractionOptions{HideWindow: true, Relative: true, Password: "", Progress: nil}
+
+	if filesystem.Exists(s.Bin) {
+		entries, code, err = sevenzip.ListWithBin(src, s.Bin, opts)
+	} else {
+		opts.Relative = false
+		entries, code, err = sevenzip.List(src, opts)
+	}
+
+	if code == sevenzip.WrongPassword {
+		return true, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return sevenzip.Encrypted(entries), nil
+}
+
+// Extract checks the listing of the archive with guard before extracting, since 7-Zip writes the files itself.
+// Links are left out by excluding them by name, and names the name policy renames are renamed once 7-Zip is done.
+func (s SevenZipExtractor) Extract(src, dest string, _ archive.Format, password string, guard *archive.Guard) error {
+	var (
+		entries []sevenzip.Entry
+		code    sevenzip.ErrorCode
+		err     error
+	)
+
+	opts := sevenzip.ExtractionOptions{HideWindow: true, Relative: true, Password: password, Progress: nil}
+	local := filesystem.Exists(s.Bin)
+
+	if local {
+		entries, _, err = sevenzip.ListWithBin(src, s.Bin, opts)
+	} else {
+		opts.Relative = false
+		entries, _, err = sevenzip.List(src, opts)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	links, renamed, err := checkEntries(entries, guard)
+	if err != nil {
+		return err
+	}
+
+	opts.Progress = progress(src)
+
+	if local {
+		code, err = sevenzip.ExtractExcludingWithBin(src, dest, s.Bin, links, false, opts)
+	} else {
+		code, err = sevenzip.ExtractExcluding(src, dest, links, false, opts)
+	}
+
+	if code == sevenzip.Warning {
+		logger.SharedLogger.Warn(lang.Lang("extractWarningNotify"), "source", src, "err", err)
+		err = nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return renameEntries(filepath.Join(dest, ArchiveName(src)), renamed)
+}
+
+// checkEntries checks the listed entries with guard. It returns the paths of the links, which are not extracted,
+// and of the entries the name policy renames.
+func checkEntries(entries []sevenzip.Entry, guard *archive.Guard) ([]string, []string, error) {
+	var links, renamed []string
+
+	for _, entry := range entries {
+		path, err := guard.Path(entry.Path)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if entry.Link {
+			guard.Skip(entry.Path)
+			links = append(links, entry.Path)
+
+			continue
+		}
+
+		if err := guard.Entry(entry.Path, entry.Size); err != nil {
+			return nil, nil, err
+		}
+
+		if !strings.HasSuffix(filepath.ToSlash(path), "/"+filesystem.Normalize(entry.Path)) {
+			renamed = append(renamed, entry.Path)
+		}
+	}
+
+	return links, renamed, nil
+}
+
+// renameEntries renames the entries 7-Zip extracted to dest under their archive names like the name policy did
+// for the guard. Each part of a path is renamed on its own, so parents are renamed before their children.
+func renameEntries(dest string, names []string) error {
+	for _, name := range names {
+		current := dest
+
+		for _, part := range strings.Split(filesystem.Normalize(name), "/") {
+			sanitized := part
+			if filesystem.CheckName(part) != nil && part != "" {
+				sanitized = filesystem.SanitizeName(part)
+			}
+
+			if sanitized != part && filesystem.Exists(filepath.Join(current, part)) {
+				if err := os.Rename(filepath.Join(current, part), filepath.Join(current, sanitized)); err != nil {
+					return err
+				}
+			}
+
+			current = filepath.Join(current, sanitized)
+		}
+	}
+
+	return nil
+}
+
+// isPasswordError returns true if err means the archive could not be decrypted, because its password is missing
+// or wrong.
+func isPasswordError(err error) bool {
+	return errors.Is(err, ErrPasswordRequired) || errors.Is(err, zip.ErrPasswordRequired) ||
+		errors.Is(err, zip.ErrWrongPassword) || errors.Is(err, sevenzip.ErrWrongPassword)
+}
+
+// isViolation returns true if err is a security violation of an archive.
+func isViolation(err error) bool {
+	var violation *archive.Violation
+	return errors.As(err, &violation)
+}
+
+// progress returns a callback that logs the extraction progress of src in steps of a quarter.
+func progress(src string) func(percent int) {
+	const step = 25
+
+	last := 0
+
+	return func(percent int) {
+		if percent-last < step {
+			return
+		}
+
+		last = percent - percent%step
+		logger.SharedLogger.Info(lang.Lang("extractProgressNotify"), "source", src, "progress", fmt.Sprintf("%d%%", percent))
+	}
+}
+
+// ArchiveName returns the base name of the archive at src without its extension, like 7-Zip names the directory.
+// Compound tar extensions are removed as a whole.
+func ArchiveName(src string) string {
+	base := filepath.Base(src)
+
+	if name, ok := tar.TrimExtension(base); ok {
+		return name
+	}
+
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
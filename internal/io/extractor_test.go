@@ -0,0 +1,163 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package io_test
+
+import (
+	stdzip "archive/zip"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hkmh223/pd2mm/common/archive"
+	"github.com/hkmh223/pd2mm/internal/data"
+	"github.com/hkmh223/pd2mm/internal/io"
+)
+
+// fakeExtractor writes a file through the guard, or fails with err after doing so.
+type fakeExtractor struct {
+	name string
+	err  error
+}
+
+func (fakeExtractor) Supports(format archive.Format) bool {
+	return format == archive.Zip
+}
+
+func (fakeExtractor) Encrypted(string, archive.Format) (bool, error) {
+	return false, nil
+}
+
+func (f fakeExtractor) Extract(_, _ string, _ archive.Format, _ string, guard *archive.Guard) error {
+	path, err := guard.Path(f.name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		return err
+	}
+
+	return f.err
+}
+
+func TestExtractArchiveFallback(t *testing.T) {
+	t.Parallel()
+
+	dest := t.TempDir()
+	src := filepath.Join(dest, "mod.zip")
+	if err := os.WriteFile(src, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	extractors := []io.Extractor{fakeExtractor{name: "native.txt", err: stdzip.ErrAlgorithm}, fakeExtractor{name: "7z.txt", err: nil}}
+
+	guard, err := io.ExtractArchive(data.Flags{}, extractors, src, dest, archive.Zip, "") //nolint:exhaustruct // reason: default flags.
+	if err != nil || guard == nil {
+		t.Fatalf("expected the second extractor to finish, got %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dest, "mod", "native.txt")); !os.IsNotExist(err) {
+		t.Fatalf("expected the files of the first extractor to be removed, got %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dest, "mod", "7z.txt")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// encrypted is a.txt, zipped with ZipCrypto and the password "secret".
+const encrypted = "" +
+	"UEsDBAoACQAAALoWU1304eFqIgAAABYAAAAFAAAAYS50eHQpksi3NdlKWW4SWBYUXF18lHK2WqMepX0FlkE3f62Ex+06UEsHCPTh" +
+	"4WoiAAAAFgAAAFBLAQIeAwoACQAAALoWU1304eFqIgAAABYAAAAFAAAAAAAAAAEAAACkgQAAAABhLnR4dFBLBQYAAAAAAQABADMA" +
+	"AABVAAAAAAA="
+
+func TestExtractPasswordProblems(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	mods := filepath.Join(dir, "mods")
+
+	if err := os.MkdirAll(mods, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	locked, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"a-missing.zip", "b-wrong.zip"} {
+		if err := os.WriteFile(filepath.Join(mods, name), locked, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	file, err := os.Create(filepath.Join(mods, "c-plain.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	writer := stdzip.NewWriter(file)
+	if _, err := writer.Create("mod.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Extract resolves the paths of the config from the working directory.
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rel, err := filepath.Rel(cwd, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	search := data.PathSearch{ //nolint:exhaustruct // reason: only the paths and passwords are used.
+		Mods:      filepath.Join(rel, "mods"),
+		Extract:   data.PathInfo{Path: filepath.Join(rel, "extract")}, //nolint:exhaustruct // reason: only the path is used.
+		Passwords: map[string]string{"b-wrong.zip": "wrong"},
+	}
+
+	problems, err := io.Extract(data.Flags{NamePolicy: "rename"}, search) //nolint:exhaustruct // reason: default flags.
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(problems) != 2 || !problems[0].Rejected || !problems[1].Rejected {
+		t.Fatalf("expected both encrypted archives to be rejected, got %v", problems)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "extract", "c-plain", "mod.txt")); err != nil {
+		t.Fatalf("expected the archive after them to be extracted: %v", err)
+	}
+}
@@ -42,6 +42,64 @@ var En = map[string]string{
 	"defaultLogPath":           "pd2mm_log.txt",
 	"watermarkPart1":           "This work is free of charge",
 	"watermarkPart2":           "If you paid money, you were scammed",
+	"skippingNotify":           "... SKIPPING",
+	"validationNotify":         "... VALIDATION FAILED",
+	"packedNotify":             "... PACKED",
+	"packUsage":                "<dir> [archive]",
+	"snapshotUsage":            "Snapshot the export directories before deploying",
+	"snapshotRetentionUsage":   "The number of snapshots to keep, 0 keeps all",
+	"snapshotCommandUsage":     "create | list | restore <id>",
+	"snapshotNotify":           "... SNAPSHOT TAKEN",
+	"snapshotEmptyNotify":      "... NO EXPORT DIRECTORIES TO SNAPSHOT",
+	"restoreNotify":            "... RESTORING",
+	"backupRetentionUsage":     "The number of save backups to keep, 0 keeps all",
+	"backupCommandUsage":       "create | list [id] | restore <id> [file]",
+	"backupNotify":             "... BACKUP TAKEN",
+	"backupFailedNotify":       "... BACKUP FAILED, NOT CLEANING OR DEPLOYING",
+	"reportNotify":             "... REPORT",
+	"luaCheckUsage":            "Check the syntax of every Lua file before deploying",
+	"bundleDBUsage":            "Path to the bundle_db.blb of the game, used to report mod_overrides of missing assets",
+	"extractMaxSizeUsage":      "The maximum uncompressed size of an archive in MiB, 0 disables the limit",
+	"extractMaxRatioUsage":     "The maximum compression ratio of an archive, 0 disables the limit",
+	"extractMaxEntriesUsage":   "The maximum number of files in an archive, 0 disables the limit",
+	"namePolicyUsage":          "What to do with file names that are invalid on Windows: rename, reject or keep",
+	"autoFixUsage":             "Fix the layout of badly packaged mods before deploying them",
+	"listUnroutedUsage":        "List every file that no rule deployed in the report, otherwise only the number per archive",
+	"strictUsage":              "Fail the run if an archive did not deploy any file",
+	"strictNotify":             "... STRICT RUN FAILED",
+	"assetsCommandUsage":       "list [hashlist] | extract <path> [dest]",
+	"modsCommandUsage":         "list",
+	"explainCommandUsage":      "<output-path>",
+	"configCommandUsage":       "migrate [file...]",
+	"configVersionNotify":      "... OLD CONFIG VERSION, RUN 'config migrate' TO UPDATE THE FILE",
+	"configMigratedNotify":     "... CONFIG MIGRATED",
+	"configCurrentNotify":      "... CONFIG IS CURRENT",
+	"unknownFieldNotify":       "... UNKNOWN CONFIG FIELD",
+	"validateCommandUsage":     "[file...]",
+	"configProblemNotify":      "... CONFIG PROBLEM",
+	"configWarningNotify":      "... CONFIG WARNING",
+	"configValidNotify":        "... CONFIG IS VALID",
+	"configSkippedNotify":      "... SKIPPING CONFIG THAT CAN NOT BE READ, RUN 'validate' FOR DETAILS",
+	"schemaCommandUsage":       "[file]",
+	"schemaWrittenNotify":      "... SCHEMA WRITTEN",
+	"defaultSchemaPath":        "pd2mm/schema.json",
+	"traceUsage":               "Record why every file was copied, for the explain command",
+	"disabledNotify":           "... DISABLED, SKIPPING",
+	"assetExtractedNotify":     "... ASSET EXTRACTED",
+	"partialDownloadNotify":    "... INCOMPLETE DOWNLOAD, SKIPPING",
+	"notArchiveNotify":         "... NOT AN ARCHIVE, SKIPPING",
+	"extractProgressNotify":    "... EXTRACTING",
+	"extractWarningNotify":     "... EXTRACTED WITH WARNINGS",
+	"unsupportedMethodNotify":  "... UNSUPPORTED COMPRESSION OR ENCRYPTION, TRYING 7ZIP",
+	"unsafeArchiveNotify":      "... UNSAFE ARCHIVE, SKIPPING",
+	"entryWarningNotify":       "... ARCHIVE ENTRY WARNING",
+	"invalidNameNotify":        "... INVALID FILE NAME",
+	"caseCollisionNotify":      "... FILE NAMES DIFFER ONLY BY CASE",
+	"encryptedArchiveNotify":   "... ENCRYPTED ARCHIVE",
+	"passwordFailedNotify":     "... ARCHIVE COULD NOT BE DECRYPTED, SKIPPING",
+	"layoutProblemNotify":      "... PACKAGING PROBLEM",
+	"layoutFixedNotify":        "... PACKAGING FIXED",
+	"passwordPrompt":           "Enter the password of the archive, leave empty to cancel",
 
 	"configLabel":        "Select from available configs",
 	"configCustomLabel":  "Set a custom config path",
@@ -51,4 +109,41 @@ var En = map[string]string{
 	"cleanExtractButton": "Clean Extract Directories",
 	"cleanExportButton":  "Clean Export Directories",
 	"cleanOutputButton":  "Clean Output Directories",
+	"unroutedLabel":      "List unrouted files",
+	"strictLabel":        "Strict",
+	"passwordTitle":      "Encrypted Archive",
+	"passwordLabel":      "Password",
+	"passwordButton":     "Extract",
+	"cancelButton":       "Cancel",
+
+	"schemaTitle":                "pd2mm config",
+	"schemaConfigSchema":         "The JSON Schema editors check the config with",
+	"schemaConfigVersion":        "The config version the file was written for, older configs are migrated when read",
+	"schemaConfigMods":           "The mod folders pd2mm deploys, each with its own directories and rules",
+	"schemaPathSearchMods":       "The folder the mod archives are read from",
+	"schemaPathSearchOutput":     "The folder mods are deployed to",
+	"schemaPathSearchExtract":    "The folder archives are extracted to",
+	"schemaPathSearchExport":     "The folder the output is copied to, such as the game's mods folder, empty to not export",
+	"schemaPathSearchInclude":    "Files whose path contains path are copied to to",
+	"schemaPathSearchExclude":    "Files whose path contains one of these are never copied",
+	"schemaPathSearchExpects":    "Paths that mark the root of a mod, the first one that matches decides where the mod is copied",
+	"schemaPathSearchCopy":       "Files or folders copied after the mods are deployed",
+	"schemaPathSearchRename":     "Replaces from by to in the destination of files whose path contains path",
+	"schemaPathSearchBackup":     "Paths backed up before the output or export folders are cleaned",
+	"schemaPathSearchJunk":       "File patterns that are never copied, added to the default junk patterns",
+	"schemaPathSearchPasswords":  "Passwords of encrypted archives, keyed by the archive file name or a pattern matching it",
+	"schemaPathSearchDisabled":   "Mods that are extracted but not deployed, by identity such as 'Archive/Folder' or a pattern",
+	"schemaPathInfoPath":         "The folder path, may use {path}, {output}, {extract} and {export}",
+	"schemaPathInfoExcludeClean": "Paths kept when the folder is cleaned",
+	"schemaIncludePath":          "The text a file path must contain, or a 'glob:' or 're:' pattern",
+	"schemaIncludeTo":            "The folder matching files are copied to",
+	"schemaExpectPath":           "The file or folder that marks the mod, or a 'glob:' or 're:' pattern",
+	"schemaExpectRequire":        "The folders the mod is placed in below the output folder",
+	"schemaExpectExclusive":      "Copy the expected folder itself instead of the folder containing it",
+	"schemaExpectBase":           "How many trailing folders of require and the copied folder are removed from the destination",
+	"schemaPathCopyFrom":         "The file or folder to copy",
+	"schemaPathCopyTo":           "Where it is copied to",
+	"schemaPathRenamePath":       "The text a file path must contain to be renamed, or a 'glob:' or 're:' pattern",
+	"schemaPathRenameFrom":       "The part of the destination that is replaced, or a 'glob:' or 're:' pattern",
+	"schemaPathRenameTo":         "The replacement, regex capture groups such as $1 can be used",
 }
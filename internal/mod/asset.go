@@ -0,0 +1,92 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package mod
+
+import (
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hkmh223/pd2mm/common/errors"
+	"github.com/hkmh223/pd2mm/common/filesystem"
+)
+
+// Asset is a Diesel asset identified by its path without extension and its type.
+type Asset struct {
+	Path string
+	Type string
+}
+
+// NewAsset creates a normalized asset of the given path and type.
+func NewAsset(name, kind string) Asset {
+	name = strings.TrimPrefix(path.Clean(filesystem.Normalize(name)), "/")
+
+	return Asset{Path: strings.ToLower(name), Type: strings.ToLower(strings.TrimPrefix(kind, "."))}
+}
+
+// String returns the asset as a file path.
+func (a Asset) String() string {
+	return a.Path + "." + a.Type
+}
+
+// Assets returns every asset the mod at dir claims, sorted and without duplicates.
+// If overrides is true the mod is a mod_overrides mod and every file below its root replaces the asset at that path,
+// unless it has a main.xml or add.xml, then its files are hooks and scripts that only the XML adds as assets.
+// Assets added by add.xml and the AddFiles of main.xml are claimed in either case.
+func Assets(dir string, overrides bool) ([]Asset, error) {
+	var assets []Asset
+
+	if overrides && !filesystem.Exists(filepath.Join(dir, MainXML)) && !filesystem.Exists(filepath.Join(dir, AddXML)) {
+		for _, file := range filesystem.GetFiles(dir) {
+			name, err := filepath.Rel(dir, file)
+			if err != nil {
+				return nil, err
+			}
+
+			name = filesystem.Normalize(name)
+			ext := path.Ext(name)
+
+			if !strings.Contains(name, "/") || ext == "" || IsJunk(name, DefaultJunk()) {
+				continue
+			}
+
+			assets = append(assets, NewAsset(strings.TrimSuffix(name, ext), ext))
+		}
+	}
+
+	for name, root := range map[string]bool{AddXML: true, MainXML: false} {
+		if !filesystem.Exists(filepath.Join(dir, name)) {
+			continue
+		}
+
+		added, err := ReadXMLAssets(filepath.Join(dir, name), root)
+		if err != nil {
+			return nil, &errors.MError{Header: "Assets", Message: "failed to parse " + name, Err: err}
+		}
+
+		assets = append(assets, added...)
+	}
+
+	slices.SortFunc(assets, func(a, b Asset) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	return slices.Compact(assets), nil
+}
@@ -0,0 +1,32 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package mod
+
+import "errors"
+
+var ErrMissingReference = errors.New("referenced file does not exist")
+
+// Packaging mistakes reported by AnalyzeLayout.
+var (
+	ErrMissingRoot   = errors.New("no mod folder was found")
+	ErrMultipleRoots = errors.New("the archive contains more than one mod")
+	ErrDoubleFolder  = errors.New("the mod is wrapped in a folder that holds nothing else")
+	ErrLooseFiles    = errors.New("files outside of the mod folder are not installed")
+	ErrModTxtDepth   = errors.New(ModTxt + " is not next to the scripts it loads")
+)
@@ -0,0 +1,61 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package mod
+
+import (
+	"path"
+	"strings"
+
+	"github.com/hkmh223/pd2mm/common/filesystem"
+)
+
+// DefaultJunk returns the file and directory names that never belong in a mod.
+func DefaultJunk() []string {
+	return []string{
+		"__MACOSX",
+		".DS_Store",
+		"._*",
+		"Thumbs.db",
+		"desktop.ini",
+		".git",
+		".gitignore",
+		".gitattributes",
+		".svn",
+		".vscode",
+		".idea",
+		"*~",
+		"*.bak",
+		"*.swp",
+		"*.orig",
+	}
+}
+
+// IsJunk returns true if any segment of name matches one of the junk patterns.
+// Patterns are compared case-insensitively and may use path.Match wildcards.
+func IsJunk(name string, patterns []string) bool {
+	for _, part := range filesystem.ToNormalizedSlice(name) {
+		for _, pattern := range patterns {
+			if matched, err := path.Match(strings.ToLower(pattern), strings.ToLower(part)); err == nil && matched {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,390 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package mod
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hkmh223/pd2mm/common/filesystem"
+)
+
+// maxFixes bounds FixLayout, the archive is analyzed again after every fix.
+const maxFixes = 16
+
+// LayoutProblem is a packaging mistake found in an extracted archive.
+type LayoutProblem struct {
+	// Path is the slash separated path of the offending file or folder, relative to the archive folder.
+	Path string
+	// Fix describes how to correct the mistake by hand.
+	Fix string
+	Err error
+
+	apply func() error
+}
+
+func (p LayoutProblem) Error() string {
+	if p.Path == "" {
+		return fmt.Sprintf("%v, %s", p.Err, p.Fix)
+	}
+
+	return fmt.Sprintf("'%s': %v, %s", p.Path, p.Err, p.Fix)
+}
+
+func (p LayoutProblem) Unwrap() error {
+	return p.Err
+}
+
+// Fixable returns true if FixLayout can correct the mistake.
+func (p LayoutProblem) Fixable() bool {
+	return p.apply != nil
+}
+
+// AnalyzeLayout reports the packaging mistakes of the extracted archive at dir.
+// A mod folder is the outermost folder that directly contains one of the markers, such as mod.txt or main.xml.
+// Files and folders matching the junk patterns are ignored.
+func AnalyzeLayout(dir string, markers, junk []string) ([]LayoutProblem, error) {
+	roots, err := Roots(dir, markers, junk)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(roots) {
+	case 0:
+		return []LayoutProblem{{
+			Path:  "",
+			Fix:   fmt.Sprintf("the archive needs one of %s, it may belong in another mods folder", strings.Join(markers, ", ")),
+			Err:   ErrMissingRoot,
+			apply: nil,
+		}}, nil
+	case 1:
+	default:
+		return []LayoutProblem{{
+			Path:  strings.Join(roots, ", "),
+			Fix:   "each folder is installed as its own mod",
+			Err:   ErrMultipleRoots,
+			apply: nil,
+		}}, nil
+	}
+
+	root := roots[0]
+
+	var problems []LayoutProblem
+
+	if problem, ok := modTxtDepth(dir, root); ok {
+		problems = append(problems, problem)
+	}
+
+	if problem, ok, err := doubleFolder(dir, root, junk); err != nil {
+		return nil, err
+	} else if ok {
+		problems = append(problems, problem)
+	}
+
+	loose, err := looseFiles(dir, root, junk)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(problems, loose...), nil
+}
+
+// FixLayout corrects the fixable mistakes AnalyzeLayout finds at dir one at a time.
+// It returns the mistakes that were fixed and the ones that are left.
+func FixLayout(dir string, markers, junk []string) ([]LayoutProblem, []LayoutProblem, error) {
+	var fixed []LayoutProblem
+
+	for len(fixed) < maxFixes {
+		problems, err := AnalyzeLayout(dir, markers, junk)
+		if err != nil {
+			return fixed, nil, err
+		}
+
+		index := slices.IndexFunc(problems, LayoutProblem.Fixable)
+		if index < 0 {
+			return fixed, problems, nil
+		}
+
+		if err := problems[index].apply(); err != nil {
+			return fixed, problems, err
+		}
+
+		fixed = append(fixed, problems[index])
+	}
+
+	problems, err := AnalyzeLayout(dir, markers, junk)
+
+	return fixed, problems, err
+}
+
+// Roots returns the slash separated path of every mod folder below dir, "." if dir is the mod folder itself.
+// Mod folders inside another mod folder belong to it and are not returned.
+func Roots(dir string, markers, junk []string) ([]string, error) {
+	var roots []string
+
+	err := filepath.WalkDir(dir, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			return err
+		}
+
+		rel = filesystem.Normalize(rel)
+		if rel != "." && IsJunk(rel, junk) {
+			return filepath.SkipDir
+		}
+
+		entries, err := os.ReadDir(name)
+		if err != nil {
+			return err
+		}
+
+		if slices.ContainsFunc(entries, func(entry fs.DirEntry) bool { return isMarker(entry.Name(), markers) }) {
+			roots = append(roots, rel)
+			return filepath.SkipDir
+		}
+
+		return nil
+	})
+
+	return roots, err
+}
+
+// RootID returns the identity of a mod, the name of the folder its archive was extracted to,
+// followed by the mod folder if the archive contains more than one mod.
+func RootID(archive, root string) string {
+	return path.Join(archive, root)
+}
+
+// modTxtDepth reports a mod.txt whose scripts only exist relative to a folder above it.
+func modTxtDepth(dir, root string) (LayoutProblem, bool) {
+	modTxt := filepath.Join(dir, root, ModTxt)
+
+	definition, err := ReadModTxt(modTxt)
+	if err != nil {
+		return LayoutProblem{}, false
+	}
+
+	scripts := definition.ScriptPaths()
+	if len(scripts) == 0 || allExist(filepath.Join(dir, root), scripts) {
+		return LayoutProblem{}, false
+	}
+
+	parents := ancestors(root)
+
+	for _, parent := range slices.Backward(parents) {
+		if !allExist(filepath.Join(dir, parent), scripts) {
+			continue
+		}
+
+		target := filepath.Join(dir, parent, ModTxt)
+
+		return LayoutProblem{
+			Path:  path.Join(root, ModTxt),
+			Fix:   fmt.Sprintf("move it up to %s", displayPath(parent)),
+			Err:   ErrModTxtDepth,
+			apply: movable(modTxt, target),
+		}, true
+	}
+
+	return LayoutProblem{}, false
+}
+
+// doubleFolder reports the outermost folder above root that holds nothing but the next folder on the way to root.
+func doubleFolder(dir, root string, junk []string) (LayoutProblem, bool, error) {
+	parts := strings.Split(root, "/")
+
+	for i, wrapper := range ancestors(root) {
+		if wrapper == "." {
+			continue
+		}
+
+		entries, err := layoutEntries(dir, wrapper, junk)
+		if err != nil {
+			return LayoutProblem{}, false, err
+		}
+
+		if len(entries) != 1 {
+			continue
+		}
+
+		child := path.Join(wrapper, parts[i])
+		target := path.Join(path.Dir(wrapper), parts[i])
+
+		problem := LayoutProblem{
+			Path:  wrapper,
+			Fix:   fmt.Sprintf("move '%s' up to replace it", child),
+			Err:   ErrDoubleFolder,
+			apply: nil,
+		}
+
+		if strings.EqualFold(target, wrapper) || !filesystem.Exists(filepath.Join(dir, target)) {
+			problem.apply = func() error { return unwrap(dir, wrapper, child, target) }
+		}
+
+		return problem, true, nil
+	}
+
+	return LayoutProblem{}, false, nil
+}
+
+// looseFiles reports the files and folders next to the folders above root that would not be installed.
+// Documentation such as readme files and previews is expected there and is not reported.
+func looseFiles(dir, root string, junk []string) ([]LayoutProblem, error) {
+	var problems []LayoutProblem
+
+	parts := strings.Split(root, "/")
+
+	for i, parent := range ancestors(root) {
+		entries, err := layoutEntries(dir, parent, junk)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			if entry == parts[i] {
+				continue
+			}
+
+			name := path.Join(parent, entry)
+
+			if !isAsset(filepath.Join(dir, name), junk) {
+				continue
+			}
+
+			problems = append(problems, LayoutProblem{
+				Path:  name,
+				Fix:   fmt.Sprintf("move it into '%s'", root),
+				Err:   ErrLooseFiles,
+				apply: movable(filepath.Join(dir, name), filepath.Join(dir, root, entry)),
+			})
+		}
+	}
+
+	return problems, nil
+}
+
+// unwrap replaces wrapper by its only folder child, which is moved to target.
+// The child is moved aside first as it usually has the same name as wrapper.
+func unwrap(dir, wrapper, child, target string) error {
+	temp := filepath.Join(dir, path.Dir(wrapper), ".pd2mm-unwrap")
+
+	if err := os.Rename(filepath.Join(dir, child), temp); err != nil {
+		return err
+	}
+
+	// Only junk is left in wrapper.
+	if err := os.RemoveAll(filepath.Join(dir, wrapper)); err != nil {
+		return err
+	}
+
+	return os.Rename(temp, filepath.Join(dir, target))
+}
+
+// movable returns a fix that moves src to dest, or nil if dest already exists.
+func movable(src, dest string) func() error {
+	if filesystem.Exists(dest) {
+		return nil
+	}
+
+	return func() error { return os.Rename(src, dest) }
+}
+
+// ancestors returns the folders above root, starting with the archive folder ".".
+func ancestors(root string) []string {
+	if root == "." {
+		return nil
+	}
+
+	parts := strings.Split(root, "/")
+	result := []string{"."}
+
+	for i := 1; i < len(parts); i++ {
+		result = append(result, strings.Join(parts[:i], "/"))
+	}
+
+	return result
+}
+
+// layoutEntries returns the names of the entries of the folder rel below dir that are not junk.
+func layoutEntries(dir, rel string, junk []string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(dir, rel))
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+
+	for _, entry := range entries {
+		if !IsJunk(entry.Name(), junk) {
+			names = append(names, entry.Name())
+		}
+	}
+
+	return names, nil
+}
+
+// isAsset returns true if name is, or is a folder containing, a file that is neither junk nor documentation.
+func isAsset(name string, junk []string) bool {
+	for _, file := range filesystem.GetFiles(name) {
+		rel, err := filepath.Rel(name, file)
+		if err != nil || IsJunk(rel, junk) {
+			continue
+		}
+
+		if !slices.Contains(documentExtensions(), strings.ToLower(filepath.Ext(file))) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// documentExtensions returns the extensions of files that describe a mod rather than belong to it.
+func documentExtensions() []string {
+	return []string{".txt", ".md", ".pdf", ".rtf", ".html", ".htm", ".url", ".png", ".jpg", ".jpeg", ".gif", ".webp"}
+}
+
+// isMarker returns true if name equals one of the markers, case-insensitively. Markers may use path.Match wildcards.
+func isMarker(name string, markers []string) bool {
+	return slices.ContainsFunc(markers, func(marker string) bool {
+		matched, err := path.Match(strings.ToLower(marker), strings.ToLower(name))
+		return strings.EqualFold(name, marker) || (err == nil && matched)
+	})
+}
+
+func allExist(dir string, references []string) bool {
+	return !slices.ContainsFunc(references, func(reference string) bool { return !filesystem.ExistsFold(dir, reference) })
+}
+
+func displayPath(rel string) string {
+	if rel == "." {
+		return "the top of the archive"
+	}
+
+	return "'" + rel + "'"
+}
@@ -0,0 +1,209 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package mod_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/hkmh223/pd2mm/internal/mod"
+)
+
+const mainXML = `<table name="Example">
+	<Hooks directory="hooks">
+		<hook file="menu.lua" source_file="lib/managers/menumanager"/>
+	</Hooks>
+	<AddFiles directory="assets">
+		<texture path="guis/textures/example"/>
+	</AddFiles>
+</table>`
+
+func TestReadXMLReferences(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), mod.MainXML)
+	if err := os.WriteFile(path, []byte(mainXML), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	references, err := mod.ReadXMLReferences(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(references, []string{"hooks/menu.lua", "assets/guis/textures/example.texture"}) {
+		t.Fatalf("unexpected references: %v", references)
+	}
+}
+
+func TestAssets(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		mod.AddXML:                      `<table><unit path="Units/Example/example"/><texture path="guis/textures/example" force="true"/></table>`,
+		"guis/textures/example.texture": "",
+		"readme.txt":                    "",
+		"units/.DS_Store":               "",
+	}
+
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	assets, err := mod.Assets(dir, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []mod.Asset{mod.NewAsset("guis/textures/example", "texture"), mod.NewAsset("units/example/example", "unit")}
+	if !slices.Equal(assets, expected) {
+		t.Fatalf("unexpected assets: %v", assets)
+	}
+}
+
+func TestAssetsXMLMods(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]map[string]string{
+		mod.MainXML: {
+			mod.MainXML:      `<table name="Example"><hooks><post hook_id="lib/managers/menumanager" script_path="hooks/menu.lua"/></hooks></table>`,
+			"hooks/menu.lua": "",
+			"loc/en.json":    "",
+		},
+		mod.AddXML: {
+			mod.AddXML:       `<table><unit path="units/example/example"/></table>`,
+			"hooks/menu.lua": "",
+		},
+	}
+
+	for name, files := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := t.TempDir()
+
+			for name, data := range files {
+				path := filepath.Join(dir, name)
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+
+				if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			assets, err := mod.Assets(dir, true)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if slices.Contains(assets, mod.NewAsset("hooks/menu", "lua")) || slices.Contains(assets, mod.NewAsset("loc/en", "json")) {
+				t.Fatalf("expected the files of an XML mod to not be claimed: %v", assets)
+			}
+		})
+	}
+}
+
+func TestIsJunk(t *testing.T) {
+	t.Parallel()
+
+	for _, path := range []string{"__MACOSX/mod.txt", "mod/.DS_Store", "mod/lua/menu.lua~", "mod/.git/HEAD"} {
+		if !mod.IsJunk(path, mod.DefaultJunk()) {
+			t.Fatalf("expected '%s' to be junk", path)
+		}
+	}
+
+	if mod.IsJunk("mod/lua/menu.lua", mod.DefaultJunk()) {
+		t.Fatal("expected 'mod/lua/menu.lua' to not be junk")
+	}
+}
+
+func TestLayout(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"Example/Example/lua/mod.txt":  `{"hooks": [{"script_path": "lua/menu.lua"}]}`,
+		"Example/Example/lua/menu.lua": "",
+		"loader.lua":                   "",
+		"readme.txt":                   "",
+		"__MACOSX/loader.lua":          "",
+	}
+
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	problems, err := mod.AnalyzeLayout(dir, []string{mod.ModTxt}, mod.DefaultJunk())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, expected := range []error{mod.ErrModTxtDepth, mod.ErrDoubleFolder, mod.ErrLooseFiles} {
+		if len(problems) != 3 || !errors.Is(problems[i], expected) {
+			t.Fatalf("unexpected problems: %v", problems)
+		}
+	}
+
+	if _, problems, err = mod.FixLayout(dir, []string{mod.ModTxt}, mod.DefaultJunk()); err != nil || len(problems) != 0 {
+		t.Fatalf("unexpected problems after fixing: %v %v", problems, err)
+	}
+
+	for _, name := range []string{"Example/mod.txt", "Example/lua/menu.lua", "Example/loader.lua", "readme.txt"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Fatalf("expected '%s' after fixing: %v", name, err)
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, "Other"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "Other", mod.ModTxt), []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if problems, err = mod.AnalyzeLayout(dir, []string{mod.ModTxt}, mod.DefaultJunk()); err != nil || len(problems) != 1 || !errors.Is(problems[0], mod.ErrMultipleRoots) {
+		t.Fatalf("expected multiple roots: %v %v", problems, err)
+	}
+
+	roots, err := mod.Roots(dir, []string{mod.ModTxt}, mod.DefaultJunk())
+	if err != nil || !slices.Equal(roots, []string{"Example", "Other"}) || mod.RootID("Pack", roots[1]) != "Pack/Other" {
+		t.Fatalf("unexpected roots: %v %v", roots, err)
+	}
+}
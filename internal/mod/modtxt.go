@@ -0,0 +1,80 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package mod
+
+import (
+	"encoding/json"
+
+	"github.com/hkmh223/pd2mm/common/filesystem"
+	"github.com/tidwall/jsonc"
+)
+
+const (
+	ModTxt  = "mod.txt"
+	MainXML = "main.xml"
+	AddXML  = "add.xml"
+)
+
+// ModDefinition is the subset of a BLT mod.txt that pd2mm needs to reason about a mod.
+//
+//nolint:tagliatelle // reason: field names are defined by BLT.
+type ModDefinition struct {
+	Name           string   `json:"name"`
+	Hooks          []Script `json:"hooks"`
+	PreHooks       []Script `json:"pre_hooks"`
+	PersistScripts []Script `json:"persist_scripts"`
+}
+
+// Script is a single hook, pre hook or persist script entry of a mod.txt.
+//
+//nolint:tagliatelle // reason: field names are defined by BLT.
+type Script struct {
+	ScriptPath string `json:"script_path"`
+}
+
+// ReadModTxt reads the mod.txt at path.
+// BLT accepts trailing commas and comments, so the file is passed through jsonc first.
+func ReadModTxt(path string) (ModDefinition, error) {
+	data, err := filesystem.ReadFile(path)
+	if err != nil {
+		return ModDefinition{}, err
+	}
+
+	definition := ModDefinition{} //nolint:exhaustruct // reason: umarshalling data into struct.
+	if err := json.Unmarshal(jsonc.ToJSON(data), &definition); err != nil {
+		return ModDefinition{}, err
+	}
+
+	return definition, nil
+}
+
+// ScriptPaths returns every script path referenced by the mod definition.
+func (m ModDefinition) ScriptPaths() []string {
+	var paths []string
+
+	for _, scripts := range [][]Script{m.Hooks, m.PreHooks, m.PersistScripts} {
+		for _, script := range scripts {
+			if script.ScriptPath != "" {
+				paths = append(paths, filesystem.Normalize(script.ScriptPath))
+			}
+		}
+	}
+
+	return paths
+}
@@ -0,0 +1,69 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package mod
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/hkmh223/pd2mm/common/errors"
+	"github.com/hkmh223/pd2mm/common/filesystem"
+)
+
+// Validate checks the mod definitions at dir and returns every problem that would stop the mod from loading.
+func Validate(dir string) []error {
+	var problems []error
+
+	if modTxt := filepath.Join(dir, ModTxt); filesystem.Exists(modTxt) {
+		definition, err := ReadModTxt(modTxt)
+		if err != nil {
+			problems = append(problems, &errors.MError{Header: "Validate", Message: "failed to parse " + ModTxt, Err: err})
+		} else {
+			problems = append(problems, MissingReferences(dir, ModTxt, definition.ScriptPaths())...)
+		}
+	}
+
+	if mainXML := filepath.Join(dir, MainXML); filesystem.Exists(mainXML) {
+		references, err := ReadXMLReferences(mainXML)
+		if err != nil {
+			problems = append(problems, &errors.MError{Header: "Validate", Message: "failed to parse " + MainXML, Err: err})
+		} else {
+			problems = append(problems, MissingReferences(dir, MainXML, references)...)
+		}
+	}
+
+	return problems
+}
+
+// MissingReferences returns an error for every reference that does not exist under dir.
+func MissingReferences(dir, source string, references []string) []error {
+	var problems []error
+
+	for _, reference := range references {
+		if !filesystem.ExistsFold(dir, reference) {
+			problems = append(problems, &errors.MError{
+				Header:  "Validate",
+				Message: fmt.Sprintf("%s references '%s'", source, reference),
+				Err:     ErrMissingReference,
+			})
+		}
+	}
+
+	return problems
+}
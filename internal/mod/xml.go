@@ -0,0 +1,135 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package mod
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+type xmlScope struct {
+	directory string
+	addFiles  bool
+}
+
+// ReadXMLReferences reads the BeardLib XML file at name and returns every file it references relative to the mod root.
+// A `directory` attribute applies to all nested elements. Elements inside AddFiles reference `<path>.<element>`,
+// any other element references its `file` attribute.
+func ReadXMLReferences(name string) ([]string, error) {
+	var references []string
+
+	err := walkXML(name, false, func(scope xmlScope, addFiles bool, element string, attributes map[string]string) {
+		switch {
+		case addFiles && attributes["path"] != "":
+			references = append(references, path.Join(scope.directory, attributes["path"]+"."+element))
+		case attributes["file"] != "":
+			references = append(references, path.Join(scope.directory, attributes["file"]))
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return references, nil
+}
+
+// ReadXMLAssets reads the BeardLib XML file at name and returns the assets added by it.
+// Every element inside AddFiles is an asset of the element type at its `path` attribute.
+// If root is true the root element is treated as AddFiles, which is the layout of add.xml.
+func ReadXMLAssets(name string, root bool) ([]Asset, error) {
+	var assets []Asset
+
+	err := walkXML(name, root, func(_ xmlScope, addFiles bool, element string, attributes map[string]string) {
+		if addFiles && attributes["path"] != "" {
+			assets = append(assets, NewAsset(attributes["path"], element))
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return assets, nil
+}
+
+// walkXML calls visit for every element of the XML file at name with the scope it is in,
+// and whether its parent is AddFiles. If root is true the root element is treated as AddFiles.
+func walkXML(name string, root bool, visit func(scope xmlScope, addFiles bool, element string, attributes map[string]string)) error {
+	file, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var scopes []xmlScope
+
+	decoder := xml.NewDecoder(file)
+
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return err
+		}
+
+		switch element := token.(type) {
+		case xml.StartElement:
+			parent := xmlScope{directory: "", addFiles: false}
+			if len(scopes) > 0 {
+				parent = scopes[len(scopes)-1]
+			}
+
+			scope := parent
+			attributes := xmlAttributes(element)
+
+			if directory, ok := attributes["directory"]; ok {
+				scope.directory = path.Join(scope.directory, directory)
+			}
+
+			if strings.EqualFold(element.Name.Local, "AddFiles") || (root && len(scopes) == 0) {
+				scope.addFiles = true
+			}
+
+			visit(scope, parent.addFiles, element.Name.Local, attributes)
+
+			scopes = append(scopes, scope)
+		case xml.EndElement:
+			scopes = scopes[:len(scopes)-1]
+		}
+	}
+
+	return nil
+}
+
+// xmlAttributes returns the attributes of an element as a map.
+func xmlAttributes(element xml.StartElement) map[string]string {
+	attributes := make(map[string]string, len(element.Attr))
+
+	for _, attribute := range element.Attr {
+		attributes[attribute.Name.Local] = attribute.Value
+	}
+
+	return attributes
+}
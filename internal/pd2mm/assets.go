@@ -0,0 +1,182 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package pd2mm
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/hkmh223/pd2mm/common/archive"
+	"github.com/hkmh223/pd2mm/common/crypto"
+	"github.com/hkmh223/pd2mm/common/diesel"
+	"github.com/hkmh223/pd2mm/common/filesystem"
+	"github.com/hkmh223/pd2mm/common/logger"
+	"github.com/hkmh223/pd2mm/internal/data"
+	"github.com/hkmh223/pd2mm/internal/lang"
+)
+
+var (
+	ErrMissingBundleDB = errors.New("no bundle database, pass -bundle-db")
+	ErrAssetNotFound   = errors.New("asset not found")
+)
+
+// GameAssets is the bundle database of the game together with the headers of every bundle next to it.
+type GameAssets struct {
+	DB      *diesel.BundleDB
+	Headers []*diesel.BundleHeader
+}
+
+// AssetsDirectory returns the default directory extracted assets are written to.
+func AssetsDirectory() string {
+	return filesystem.Combine(lang.Lang("programName"), "assets")
+}
+
+// OpenGameAssets reads the bundle database at name and the bundle headers in the same directory.
+func OpenGameAssets(name string) (*GameAssets, error) {
+	if name == "" {
+		return nil, &MError{Header: "OpenGameAssets", Message: "bundle database is not set", Err: ErrMissingBundleDB}
+	}
+
+	db, err := diesel.ReadBundleDB(name)
+	if err != nil {
+		return nil, &MError{Header: "OpenGameAssets", Message: "failed to read bundle database '" + name + "'", Err: err}
+	}
+
+	headers, err := diesel.ReadBundleHeaders(filepath.Dir(name))
+	if err != nil {
+		return nil, &MError{Header: "OpenGameAssets", Message: "failed to read bundle headers", Err: err}
+	}
+
+	return &GameAssets{DB: db, Headers: headers}, nil
+}
+
+// Extract writes the asset at name, a path with extension such as `units/example/example.unit`,
+// to the same path under dest so that dest can be used as a mod_overrides mod.
+func (a *GameAssets) Extract(name, dest string) (string, error) {
+	name = filesystem.Normalize(name)
+	ext := path.Ext(name)
+
+	file, ok := a.DB.Find(strings.TrimSuffix(name, ext), ext)
+	if !ok {
+		return "", &MError{Header: "Extract", Message: "'" + name + "' is not in the bundle database", Err: ErrAssetNotFound}
+	}
+
+	for _, header := range a.Headers {
+		entry, ok := header.Find(file.ID)
+		if !ok {
+			continue
+		}
+
+		content, err := header.ReadEntry(entry)
+		if err != nil {
+			return "", &MError{Header: "Extract", Message: "failed to read '" + name + "' from '" + header.Payload + "'", Err: err}
+		}
+
+		target, err := archive.Join(dest, name)
+		if err != nil {
+			return "", &MError{Header: "Extract", Message: "'" + name + "' is outside of '" + dest + "'", Err: err}
+		}
+
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil { //nolint:mnd // reason: directory permission.
+			return "", err
+		}
+
+		if err := filesystem.WriteFile(target, content, 0o644); err != nil { //nolint:mnd // reason: file permission.
+			return "", err
+		}
+
+		return target, nil
+	}
+
+	return "", &MError{Header: "Extract", Message: fmt.Sprintf("'%s' with id %d is in no bundle", name, file.ID), Err: ErrAssetNotFound}
+}
+
+// Names returns the path of every asset in the bundle database, resolved through hashlist when it is not nil.
+// Hashes that cannot be resolved are written as 16 hexadecimal digits.
+func (a *GameAssets) Names(hashlist *crypto.Hashlist) []string {
+	resolve := func(hash uint64) string {
+		if hashlist != nil {
+			if str, ok := hashlist.Lookup(hash); ok {
+				return str
+			}
+		}
+
+		return crypto.FormatIDString(hash)
+	}
+
+	names := make([]string, 0, len(a.DB.Files))
+	for _, file := range a.DB.Files {
+		names = append(names, resolve(file.Path)+"."+resolve(file.Extension))
+	}
+
+	return names
+}
+
+func assetsCommand(args []string) error {
+	switch args[0] {
+	case "list":
+		assets, err := OpenGameAssets(data.Flag.BundleDB)
+		if err != nil {
+			return err
+		}
+
+		var hashlist *crypto.Hashlist
+
+		if len(args) > 1 {
+			if hashlist, err = crypto.ReadHashlist(args[1]); err != nil {
+				return err
+			}
+		}
+
+		for _, name := range assets.Names(hashlist) {
+			logger.SharedLogger.Info(name)
+		}
+
+		return nil
+	case "extract":
+		if len(args) < 2 { //nolint:mnd // reason: extract requires a path.
+			return &MError{Header: "assets", Message: "usage: assets extract <path> [dest]", Err: ErrMissingArguments}
+		}
+
+		dest := AssetsDirectory()
+		if len(args) > 2 { //nolint:mnd // reason: dest is optional.
+			dest = args[2]
+		}
+
+		assets, err := OpenGameAssets(data.Flag.BundleDB)
+		if err != nil {
+			return err
+		}
+
+		target, err := assets.Extract(args[1], dest)
+		if err != nil {
+			return err
+		}
+
+		logger.SharedLogger.Info(lang.Lang("assetExtractedNotify"), "path", target)
+
+		return nil
+	}
+
+	return &MError{Header: "assets", Message: fmt.Sprintf("'%s', expected one of: list, extract", args[0]), Err: ErrUnknownCommand}
+}
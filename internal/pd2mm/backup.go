@@ -0,0 +1,261 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package pd2mm
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hkmh223/pd2mm/common/filesystem"
+	"github.com/hkmh223/pd2mm/common/logger"
+	"github.com/hkmh223/pd2mm/internal/data"
+	"github.com/hkmh223/pd2mm/internal/io"
+	"github.com/hkmh223/pd2mm/internal/lang"
+)
+
+var (
+	ErrBackupNotFound = errors.New("backup not found")
+	ErrBackupFailed   = errors.New("saves could not be backed up, nothing was cleaned so they are not lost")
+)
+
+type Backup struct {
+	ID      string        `json:"id"`
+	Created time.Time     `json:"created"`
+	Entries []BackupEntry `json:"entries"`
+}
+
+type BackupEntry struct {
+	Directory string `json:"directory"`
+	Path      string `json:"path"`
+}
+
+// BackupDirectory returns the directory save backups are stored in.
+func BackupDirectory() string {
+	return filesystem.Combine(lang.Lang("programName"), "backups")
+}
+
+// TakeBackup copies every existing Backup path of searches into a new timestamped backup, then prunes backups beyond retention.
+// Nothing is written if none of the paths exist.
+func TakeBackup(searches []data.PathSearch, retention int) (Backup, error) {
+	var paths []string
+
+	for _, search := range searches {
+		for _, backup := range search.Backup {
+			path, ok := search.FormatPath(backup)
+			if !ok || !filesystem.Exists(path) {
+				continue
+			}
+
+			if !slices.ContainsFunc(paths, func(item string) bool { return filepath.Clean(item) == filepath.Clean(path) }) {
+				paths = append(paths, path)
+			}
+		}
+	}
+
+	if len(paths) == 0 {
+		return Backup{ID: "", Created: time.Now(), Entries: []BackupEntry{}}, nil
+	}
+
+	dir := BackupDirectory()
+
+	id, err := newHistoryID(dir)
+	if err != nil {
+		return Backup{}, err
+	}
+
+	backup := Backup{ID: id, Created: time.Now(), Entries: []BackupEntry{}}
+
+	for index, path := range paths {
+		entry := BackupEntry{Directory: strconv.Itoa(index), Path: filesystem.Normalize(path)}
+
+		if err := io.CopyFile(path, filepath.Join(dir, id, entry.Directory)); err != nil {
+			return Backup{}, discardHistory(dir, id, &MError{Header: "TakeBackup", Message: fmt.Sprintf("failed to back up '%s'", path), Err: err})
+		}
+
+		backup.Entries = append(backup.Entries, entry)
+	}
+
+	if err := writeManifest(dir, id, backup); err != nil {
+		return Backup{}, discardHistory(dir, id, err)
+	}
+
+	logger.SharedLogger.Info(lang.Lang("backupNotify"), "id", id, "entries", len(backup.Entries))
+
+	return backup, pruneHistory(dir, retention)
+}
+
+// backupBeforeClean backs up the saves of configs before their Output or Export directories are cleaned, since saves
+// live there. A failed backup is returned as ErrBackupFailed, and the caller must not clean anything.
+func backupBeforeClean(configs []Config, path int) error {
+	if path == Extract {
+		return nil
+	}
+
+	if _, err := TakeBackup(searches(configs), data.Flag.BackupRetention); err != nil {
+		logger.SharedLogger.Error(lang.Lang("backupFailedNotify"), "err", err)
+		return &MError{Header: "backup", Message: err.Error(), Err: ErrBackupFailed}
+	}
+
+	return nil
+}
+
+// Backups returns every stored backup, oldest first.
+func Backups() ([]Backup, error) {
+	ids, err := historyIDs(BackupDirectory())
+	if err != nil {
+		return nil, err
+	}
+
+	backups := make([]Backup, 0, len(ids))
+
+	for _, id := range ids {
+		backup, err := readManifest[Backup](BackupDirectory(), id)
+		if err != nil {
+			return nil, err
+		}
+
+		backups = append(backups, backup)
+	}
+
+	return backups, nil
+}
+
+// BackupFiles returns every file stored in the backup, mapped from its original path to its path in the backup.
+func BackupFiles(backup Backup) map[string]string {
+	files := map[string]string{}
+
+	for _, entry := range backup.Entries {
+		root := filepath.Join(BackupDirectory(), backup.ID, entry.Directory)
+
+		for _, file := range filesystem.GetFiles(root) {
+			relative, err := filepath.Rel(root, file)
+			if err != nil {
+				continue
+			}
+
+			files[filesystem.Normalize(filepath.Join(entry.Path, relative))] = file
+		}
+	}
+
+	return files
+}
+
+// RestoreBackup copies the files of backup id back to where they were taken from.
+// If name is set only files whose original path equals or ends with name are restored.
+func RestoreBackup(id, name string) error {
+	dir := BackupDirectory()
+
+	if found, err := hasHistoryID(dir, id); err != nil || !found {
+		return &MError{Header: "RestoreBackup", Message: fmt.Sprintf("'%s'", id), Err: errors.Join(ErrBackupNotFound, err)}
+	}
+
+	backup, err := readManifest[Backup](dir, id)
+	if err != nil {
+		return err
+	}
+
+	name = filesystem.Normalize(name)
+	restored := 0
+
+	for original, file := range BackupFiles(backup) {
+		if name != "" && original != name && !strings.HasSuffix(original, "/"+name) {
+			continue
+		}
+
+		logger.SharedLogger.Info(lang.Lang("restoreNotify"), "id", id, "path", original)
+
+		if err := io.CopyFile(file, original); err != nil {
+			return &MError{Header: "RestoreBackup", Message: fmt.Sprintf("failed to restore '%s'", original), Err: err}
+		}
+
+		restored++
+	}
+
+	if restored == 0 {
+		return &MError{Header: "RestoreBackup", Message: fmt.Sprintf("no file matching '%s' in '%s'", name, id), Err: ErrBackupNotFound}
+	}
+
+	return nil
+}
+
+// backupCommand handles `backup create`, `backup list [id]` and `backup restore <id> [file]`.
+func backupCommand(args []string) error {
+	switch args[0] {
+	case "create":
+		configs, err := Configs(Flags{Flags: data.Flag})
+		if err != nil {
+			return err
+		}
+
+		_, err = TakeBackup(searches(configs), data.Flag.BackupRetention)
+
+		return err
+	case "list":
+		backups, err := Backups()
+		if err != nil {
+			return err
+		}
+
+		for _, backup := range backups {
+			if len(args) > 1 && args[1] != backup.ID {
+				continue
+			}
+
+			logger.SharedLogger.Info(backup.ID, "created", backup.Created.Format(time.DateTime), "entries", len(backup.Entries))
+
+			if len(args) > 1 {
+				for _, original := range slices.Sorted(maps.Keys(BackupFiles(backup))) {
+					logger.SharedLogger.Info(original)
+				}
+			}
+		}
+
+		return nil
+	case "restore":
+		if len(args) < 2 { //nolint:mnd // reason: restore requires an id.
+			return &MError{Header: "backup", Message: "usage: backup restore <id> [file]", Err: ErrMissingArguments}
+		}
+
+		name := ""
+		if len(args) > 2 { //nolint:mnd // reason: file is optional.
+			name = args[2]
+		}
+
+		return RestoreBackup(args[1], name)
+	}
+
+	return &MError{Header: "backup", Message: fmt.Sprintf("'%s', expected one of: create, list, restore", args[0]), Err: ErrUnknownCommand}
+}
+
+// searches returns every PathSearch of configs.
+func searches(configs []Config) []data.PathSearch {
+	var result []data.PathSearch
+
+	for _, config := range configs {
+		result = append(result, config.Mods...)
+	}
+
+	return result
+}
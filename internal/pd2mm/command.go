@@ -0,0 +1,86 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package pd2mm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hkmh223/pd2mm/internal/lang"
+)
+
+var (
+	ErrUnknownCommand   = errors.New("unknown command")
+	ErrMissingArguments = errors.New("missing arguments")
+)
+
+type Command struct {
+	Name  string
+	Usage string
+	Args  int
+	Run   func(args []string) error
+}
+
+// Commands returns every command the console app accepts after its flags.
+func Commands() []Command {
+	return []Command{
+		{Name: "pack", Usage: lang.Lang("packUsage"), Args: 1, Run: packCommand},
+		{Name: "backup", Usage: lang.Lang("backupCommandUsage"), Args: 1, Run: backupCommand},
+		{Name: "snapshot", Usage: lang.Lang("snapshotCommandUsage"), Args: 1, Run: snapshotCommand},
+		{Name: "assets", Usage: lang.Lang("assetsCommandUsage"), Args: 1, Run: assetsCommand},
+		{Name: "mods", Usage: lang.Lang("modsCommandUsage"), Args: 1, Run: modsCommand},
+		{Name: "explain", Usage: lang.Lang("explainCommandUsage"), Args: 1, Run: explainCommand},
+		{Name: "config", Usage: lang.Lang("configCommandUsage"), Args: 1, Run: configCommand},
+		{Name: "schema", Usage: lang.Lang("schemaCommandUsage"), Args: 0, Run: schemaCommand},
+		{Name: "validate", Usage: lang.Lang("validateCommandUsage"), Args: 0, Run: validateCommand},
+	}
+}
+
+// RunCommand runs the command named by the first argument with the remaining arguments.
+func RunCommand(args []string) error {
+	if len(args) == 0 {
+		return &MError{Header: "RunCommand", Message: "no command given", Err: ErrMissingArguments}
+	}
+
+	for _, command := range Commands() {
+		if command.Name != args[0] {
+			continue
+		}
+
+		if len(args)-1 < command.Args {
+			return &MError{Header: "RunCommand", Message: fmt.Sprintf("usage: %s %s", command.Name, command.Usage), Err: ErrMissingArguments}
+		}
+
+		return command.Run(args[1:])
+	}
+
+	return &MError{Header: "RunCommand", Message: fmt.Sprintf("'%s', expected one of: %s", args[0], commandNames()), Err: ErrUnknownCommand}
+}
+
+// commandNames returns the names of every command joined by a comma.
+func commandNames() string {
+	var names []string
+
+	for _, command := range Commands() {
+		names = append(names, command.Name)
+	}
+
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,81 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package pd2mm
+
+import (
+	"fmt"
+
+	"github.com/hkmh223/pd2mm/common/logger"
+	"github.com/hkmh223/pd2mm/internal/data"
+	"github.com/hkmh223/pd2mm/internal/lang"
+)
+
+// configCommand handles `config migrate [file...]`, without files every config in use is migrated.
+func configCommand(args []string) error {
+	if args[0] != "migrate" {
+		return &MError{Header: "config", Message: fmt.Sprintf("'%s', expected one of: migrate", args[0]), Err: ErrUnknownCommand}
+	}
+
+	paths := args[1:]
+
+	if len(paths) == 0 {
+		names, err := ConfigNames(Flags{Flags: data.Flag})
+		if err != nil {
+			return err
+		}
+
+		paths = names
+	}
+
+	for _, path := range paths {
+		version, fields, err := data.MigrateFile(path)
+		if err != nil {
+			return &MError{Header: "config", Message: "failed to migrate '" + path + "'", Err: err}
+		}
+
+		for _, field := range fields {
+			logger.SharedLogger.Warn(lang.Lang("unknownFieldNotify"), "path", path, "field", field)
+		}
+
+		if version == data.ConfigVersion {
+			logger.SharedLogger.Info(lang.Lang("configCurrentNotify"), "path", path, "version", version)
+			continue
+		}
+
+		logger.SharedLogger.Info(lang.Lang("configMigratedNotify"), "path", path, "from", version, "to", data.ConfigVersion)
+	}
+
+	return nil
+}
+
+// schemaCommand handles `schema [file]`, which writes the JSON Schema of configs to file or next to the default config.
+func schemaCommand(args []string) error {
+	path := lang.Lang("defaultSchemaPath")
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	if err := data.WriteSchema(path); err != nil {
+		return &MError{Header: "schema", Message: "failed to write '" + path + "'", Err: err}
+	}
+
+	logger.SharedLogger.Info(lang.Lang("schemaWrittenNotify"), "path", path)
+
+	return nil
+}
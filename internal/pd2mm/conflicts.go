@@ -0,0 +1,95 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package pd2mm
+
+import (
+	"errors"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hkmh223/pd2mm/common/filesystem"
+	"github.com/hkmh223/pd2mm/internal/mod"
+)
+
+var ErrAssetConflict = errors.New("asset is replaced by more than one mod")
+
+// CheckConflicts collects the assets claimed by every mod in the Output directories of the config.
+// Assets claimed by more than one mod are added to the SharedReport as warnings of each of those mods.
+func (c Config) CheckConflicts() error {
+	claims := map[mod.Asset][]string{}
+	visited := map[string]bool{}
+
+	for _, search := range c.Mods {
+		output, err := filesystem.FromCwd(search.Output.Path)
+		if err != nil {
+			return err
+		}
+
+		if visited[output] || !filesystem.Exists(output) {
+			continue
+		}
+
+		visited[output] = true
+
+		directories, err := filesystem.GetTopDirectories(output)
+		if err != nil {
+			return &MError{Header: "CheckConflicts", Message: "failed to get directories '" + search.Output.Path + "'", Err: err}
+		}
+
+		overrides := isModOverrides(PathSearch{PathSearch: &search})
+
+		for _, directory := range directories {
+			root := filepath.Join(output, directory)
+			id := _mods.output(root, directory)
+
+			assets, err := mod.Assets(root, overrides)
+			if err != nil {
+				SharedReport.AddWarning(id, err)
+				continue
+			}
+
+			for _, asset := range assets {
+				claims[asset] = append(claims[asset], id)
+			}
+		}
+	}
+
+	assets := slices.SortedFunc(maps.Keys(claims), func(a, b mod.Asset) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	for _, asset := range assets {
+		mods := slices.Compact(slices.Sorted(slices.Values(claims[asset])))
+		if len(mods) < 2 { //nolint:mnd // reason: a conflict needs two mods.
+			continue
+		}
+
+		for _, name := range mods {
+			SharedReport.AddWarning(name, &MError{
+				Header:  "CheckConflicts",
+				Message: asset.String() + " is claimed by " + strings.Join(mods, ", "),
+				Err:     ErrAssetConflict,
+			})
+		}
+	}
+
+	return nil
+}
@@ -19,6 +19,7 @@
 package pd2mm
 
 import (
+	"flag"
 	"io"
 	"os"
 
@@ -33,6 +34,7 @@ import (
 //nolint:cyclop // reason: setup
 func StartConsoleApp(logFile io.Writer, version func()) {
 	logger.RegisterLogger(logFile, os.Stdout)
+	setupPasswordPrompt()
 
 	errCh := make(chan error, 1)
 
@@ -54,6 +56,14 @@ func StartConsoleApp(logFile io.Writer, version func()) {
 		data.Flag.Config = ""
 	}
 
+	if flag.NArg() > 0 {
+		if err := RunCommand(flag.Args()); err != nil {
+			logger.SharedLogger.Fatal(err)
+		}
+
+		return
+	}
+
 	configs, err := Configs(Flags{Flags: data.Flag})
 	if err != nil {
 		logger.SharedLogger.Fatal(err)
@@ -82,9 +92,16 @@ func StartConsoleApp(logFile io.Writer, version func()) {
 
 	Flags{Flags: data.Flag}.RunWithError(configs, errCh)
 
+	failed := false
+
 	for err := range errCh {
 		if err != nil {
 			logger.SharedLogger.Errorf("%s %v", lang.Lang("errorNotify"), err)
+			failed = true
 		}
 	}
+
+	if failed && data.Flag.Strict {
+		logger.SharedLogger.Fatal(lang.Lang("strictNotify"))
+	}
 }
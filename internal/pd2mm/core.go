@@ -32,6 +32,7 @@ import (
 	"github.com/hkmh223/pd2mm/internal/data"
 	"github.com/hkmh223/pd2mm/internal/io"
 	"github.com/hkmh223/pd2mm/internal/lang"
+	"github.com/hkmh223/pd2mm/internal/mod"
 )
 
 type MError = errors.MError
@@ -63,12 +64,40 @@ func (c Config) process(search PathSearch) error {
 	}
 
 	for _, directory := range directories {
-		if err := c.checkIncludeData(filesystem.Normalize(filepath.Join(search.Extract.Path, directory)), search); err != nil {
-			return err
+		path := filesystem.Normalize(filepath.Join(search.Extract.Path, directory))
+		roots := search.modRoots(path)
+
+		for _, root := range roots {
+			_mods.add(filepath.Join(path, root), mod.RootID(directory, root))
+		}
+
+		for _, root := range roots {
+			id := mod.RootID(directory, root)
+
+			if search.IsDisabled(id) {
+				logger.SharedLogger.Info(lang.Lang("disabledNotify"), "mod", id)
+				SharedReport.SetDisabled(id)
+				_routes.skip(filepath.Join(path, root))
+
+				continue
+			}
+
+			if err := c.checkIncludeData(filesystem.Normalize(filepath.Join(path, root)), search); err != nil {
+				return err
+			}
+		}
+	}
+
+	if data.Flag.LuaCheck {
+		if err := c.CheckLua(search); err != nil {
+			logger.SharedLogger.Error("failed to check lua files", "err", err)
 		}
 	}
 
 	if search.Export.Path != "" {
+		SharedTrace.Add(search, search.Output.Path, search.Export.Path, newTraceCopy(traceExport, search.Output.Path,
+			fmt.Sprintf("the output folder '%s' is copied to the export folder '%s'", search.Output.Path, search.Export.Path)))
+
 		if err := io.CopyFile(search.Output.Path, search.Export.Path); err != nil {
 			return &MError{Header: "process", Message: fmt.Sprintf("failed to copy '%s' to '%s'", search.Output.Path, search.Export.Path), Err: err}
 		}
@@ -89,12 +118,19 @@ func (c Config) checkIncludeData(path string, search PathSearch) error {
 	for _, file := range files {
 		source := filesystem.Normalize(file)
 
+		// Junk is skipped before routing, so a junk copy of mod.txt can not decide where the mod goes.
+		if rel, err := filepath.Rel(cwd, file); err == nil && search.isJunk(rel) {
+			continue
+		}
+
 		for _, include := range search.Include {
-			if !strings.Contains(source, search.FormatString(include.Path)) {
+			if !search.includes(source, include.Path) {
 				continue
 			}
 
-			if err := c.copyExpected(source, search.FormatString(include.To), false, search); err != nil {
+			trace := newTraceCopy("include", include.Path, fmt.Sprintf("'%s' matches the include path '%s'", source, search.FormatString(include.Path)))
+
+			if err := c.copyExpected(source, search.FormatString(include.To), false, search, trace); err != nil {
 				logger.SharedLogger.Error("failed to copy", "source", source, "destination", search.FormatString(include.To), "err", err)
 			}
 		}
@@ -112,7 +148,11 @@ func (c Config) checkExcludeData(path string, search PathSearch) bool {
 	parts := strings.Split(filesystem.Normalize(path), "/")
 
 	for _, exclude := range search.Exclude {
-		if util.ContainsSubslice(parts, search.FormatSlice(filesystem.ToNormalizedSlice(exclude))) {
+		if compiled, ok := rulePattern(exclude); ok {
+			if compiled.Match(search.archiveParts(path)) {
+				return false
+			}
+		} else if util.ContainsSubslice(parts, search.FormatSlice(filesystem.ToNormalizedSlice(exclude))) {
 			return false
 		}
 	}
@@ -131,6 +171,16 @@ func (c Config) checkExpectsData(path string, search PathSearch) (bool, error) {
 	source := strings.Split(path, "/")
 
 	for _, expect := range search.Expects {
+		if compiled, ok := rulePattern(expect.Path); ok {
+			parts := search.archiveParts(path)
+
+			if start, _ := compiled.Find(parts); start >= 0 {
+				return c.expectedIsDirectory(source, search, expect, len(source)-len(parts)+start)
+			}
+
+			continue
+		}
+
 		expectPath := filesystem.ToNormalizedSlice(expect.Path)
 
 		if len(source) < len(expectPath) {
@@ -140,7 +190,7 @@ func (c Config) checkExpectsData(path string, search PathSearch) (bool, error) {
 		if slices.Contains(expectPath, filesystem.GetFileExtension(safe.Slice(source, len(source)-1))) {
 			return c.expectedIsFile(source, search, expect)
 		} else if util.Matches(source, expectPath) == len(expectPath) {
-			return c.expectedIsDirectory(source, search, expect)
+			return c.expectedIsDirectory(source, search, expect, safe.HasIndex(source, safe.Slice(expectPath, 0)))
 		}
 	}
 
@@ -151,7 +201,7 @@ func (c Config) checkExpectsData(path string, search PathSearch) (bool, error) {
 func (c Config) expectedIsFile(source []string, search PathSearch, expect data.Expect) (bool, error) {
 	path := strings.Join(source, "/")
 
-	destination := filepath.Join(search.Output.Path, fixDestination(source, search, expect, false))
+	destination := filepath.Join(search.Output.Path, fixDestination(source, search, expect, false, 0))
 	if destination == "" {
 		return false, nil
 	}
@@ -164,23 +214,30 @@ func (c Config) expectedIsFile(source []string, search PathSearch, expect data.E
 	destination = filesystem.Normalize(cwd)
 	expectRequire := filesystem.ToNormalizedSlice(expect.Require)
 
+	trace := newTraceCopy("expects", expect.Path,
+		fmt.Sprintf("'%s' matched the expected file '%s'", path, expect.Path),
+		fmt.Sprintf("destination is the output '%s' joined with require '%s' and the file name", search.Output.Path, expect.Require),
+	)
+
 	if util.ContainsSubslice(source, expectRequire) && util.ContainsSubslice(strings.Split(destination, "/"), expectRequire) {
 		path = strings.Join(safe.Range(source, 0, len(source)-len(expectRequire)), "/")
 
 		dest := strings.Split(destination, "/")
 		destination = strings.Join(safe.Range(dest, 0, len(dest)-len(expectRequire)), "/")
+
+		trace.Steps = append(trace.Steps, fmt.Sprintf("require '%s' is removed from the end of the source and the destination", expect.Require))
 	}
 
-	if err := c.copyExpected(path, destination, false, search); err != nil {
+	if err := c.copyExpected(path, destination, false, search, trace); err != nil {
 		return false, &MError{Header: "expects", Message: fmt.Sprintf("Failed to copy '%s' to '%s'", path, destination), Err: err}
 	}
 
 	return true, nil
 }
 
-// Handle expected data as a directory.
-func (c Config) expectedIsDirectory(source []string, search PathSearch, expect data.Expect) (bool, error) {
-	destination := fixDestination(source, search, expect, true)
+// Handle expected data as a directory, index is the position of the expected path in source.
+func (c Config) expectedIsDirectory(source []string, search PathSearch, expect data.Expect, index int) (bool, error) {
+	destination := fixDestination(source, search, expect, true, index)
 	if destination == "" {
 		return false, nil
 	}
@@ -190,14 +247,21 @@ func (c Config) expectedIsDirectory(source []string, search PathSearch, expect d
 		return false, err
 	}
 
-	index := safe.HasIndex(source, safe.Slice(filesystem.ToNormalizedSlice(expect.Path), 0))
 	src := strings.Join(safe.Range(source, 0, index), "/")
 
+	trace := newTraceCopy("expects", expect.Path,
+		fmt.Sprintf("'%s' matched the expected path '%s'", strings.Join(source, "/"), expect.Path),
+		fmt.Sprintf("the folder containing '%s' is copied", safe.Slice(source, index)),
+	)
+
 	if expect.Exclusive {
 		src = strings.Join(safe.Range(source, 0, index+1), "/")
+		trace.Steps[1] = fmt.Sprintf("'%s' itself is copied as the expects rule is exclusive", safe.Slice(source, index))
 	}
 
-	if err := c.copyExpected(src, destination, false, search); err != nil {
+	trace.Steps = append(trace.Steps, fmt.Sprintf("destination is the output '%s' joined with require '%s' and the copied folder, base %d removes as many trailing parts", search.Output.Path, expect.Require, expect.Base))
+
+	if err := c.copyExpected(src, destination, false, search, trace); err != nil {
 		return false, &MError{Header: "expectedIsDirectory", Message: fmt.Sprintf("Failed to copy '%s' to '%s'", src, destination), Err: err}
 	}
 
@@ -212,20 +276,28 @@ func (c Config) copyAdditional(search PathSearch) error {
 		logger.SharedLogger.Info(lang.Lang("copyingNotify"), "source", search.FormatString(copy.From), "destination", search.FormatString(copy.To))
 
 		src, dest := search.FormatString(copy.From), search.FormatString(copy.To)
-		if err := io.CopyFile(src, dest); err != nil {
+		_routes.copy(src)
+		trace := newTraceCopy("copy", copy.From, fmt.Sprintf("the copy rule copies '%s' to '%s'", copy.From, copy.To))
+		trace.Junk = search.junk()
+		SharedTrace.Add(search, src, dest, trace)
+
+		filtered, err := io.CopyFiltered(src, dest, search.isJunk)
+		if err != nil {
 			return &MError{Header: "copyAdditional", Message: fmt.Sprintf("failed to copy '%s' to '%s'", src, dest), Err: err}
 		}
+
+		SharedReport.AddFiltered(_mods.id(src, filepath.Base(src)), filtered)
 	}
 
 	return nil
 }
 
 // fixDestination fixes the destination path based on the provided PathSearch and Expect data.
-func fixDestination(parts []string, search PathSearch, expect data.Expect, dir bool) string {
+// For directories index is the position of the expected path in parts.
+func fixDestination(parts []string, search PathSearch, expect data.Expect, dir bool, index int) string {
 	result := strings.Join(parts, "/")
 
 	if dir {
-		index := safe.HasIndex(parts, safe.Slice(filesystem.ToNormalizedSlice(expect.Path), 0))
 		result = strings.Join(safe.Range(parts, 0, index), "/")
 
 		if expect.Exclusive {
@@ -246,19 +318,15 @@ func fixDestination(parts []string, search PathSearch, expect data.Expect, dir b
 }
 
 // copyExpected copies the source file or directory to the destination based on the provided PathSearch.
-func (c Config) copyExpected(src, dest string, expected bool, search PathSearch) error {
+func (c Config) copyExpected(src, dest string, expected bool, search PathSearch, trace TraceCopy) error {
 	src = filesystem.Normalize(src)
 	dest = filesystem.Normalize(dest)
 
 	for _, rename := range search.Rename {
-		pathFmt := search.FormatSlice(filesystem.ToNormalizedSlice(rename.Path))
-		fromFmt := search.FormatSlice(filesystem.ToNormalizedSlice(rename.From))
-		toFmt := search.FormatSlice(filesystem.ToNormalizedSlice(rename.To))
-
-		parts := strings.Split(src, "/")
-		if util.ContainsSubslice(parts, pathFmt) {
-			result := util.ReplaceSubslice(strings.Split(dest, "/"), fromFmt, toFmt)
-			dest = strings.Join(result, "/")
+		if search.renames(src, rename.Path) {
+			dest = search.renameDestination(dest, rename.From, rename.To)
+
+			trace.Steps = append(trace.Steps, fmt.Sprintf("rename '%s' replaced '%s' by '%s', giving '%s'", rename.Path, rename.From, rename.To, dest))
 		}
 	}
 
@@ -269,14 +337,31 @@ func (c Config) copyExpected(src, dest string, expected bool, search PathSearch)
 	}
 
 	logger.SharedLogger.Info(lang.Lang("copyingNotify"), "source", src, "destination", dest)
+	_routes.copy(src)
+	_mods.deploy(src, dest, search.Output.Path)
+	trace.Junk = search.junk()
+	SharedTrace.Add(search, src, dest, trace)
 
-	if err := io.CopyFile(src, dest); err != nil {
+	filtered, err := io.CopyFiltered(src, dest, search.isJunk)
+	if err != nil {
 		return &MError{Header: "copyExpected", Message: fmt.Sprintf("failed to copy '%s' to '%s'", src, dest), Err: err}
 	}
 
+	SharedReport.AddFiltered(_mods.id(src, filepath.Base(src)), filtered)
+
 	return nil
 }
 
+// isJunk returns true if path matches the default junk patterns or the junk patterns of the PathSearch.
+func (search PathSearch) isJunk(path string) bool {
+	return mod.IsJunk(path, search.junk())
+}
+
+// junk returns the default junk patterns and the junk patterns of the PathSearch.
+func (search PathSearch) junk() []string {
+	return append(mod.DefaultJunk(), search.Junk...)
+}
+
 // Parse expected file paths and copy them.
 func (c Config) parseExpectedAndCopy(src, dest string) error {
 	parts := strings.Split(src, "/")
@@ -285,6 +370,7 @@ func (c Config) parseExpectedAndCopy(src, dest string) error {
 	// Combine the normalized destination with the source directory name
 	src, dest = strings.Join(source, "/"), filepath.Join(dest, safe.Slice(source, len(source)-1))
 	logger.SharedLogger.Info(lang.Lang("copyingNotify"), "source", src, "destination", dest)
+	_routes.copy(src)
 
 	if err := io.CopyFile(src, dest); err != nil {
 		return &MError{Header: "parseExpectedAndCopy", Message: fmt.Sprintf("failed to copy '%s' to '%s'", src, dest), Err: err}
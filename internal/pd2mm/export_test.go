@@ -0,0 +1,39 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package pd2mm
+
+// RunProcess exposes runProcess to the tests, the shared state of a run is reset first as RunWithError does.
+func RunProcess(config Config) error {
+	SharedReport.Reset()
+	_routes.reset()
+	_mods.reset()
+	SharedTrace.Reset()
+
+	return runProcess(config)
+}
+
+// ModID exposes the identity of the extracted mod at path to the tests.
+func ModID(path, fallback string) string {
+	return _mods.id(path, fallback)
+}
+
+// ModOutput exposes the identity of the mod deployed to the Output folder dir to the tests.
+func ModOutput(dir, fallback string) string {
+	return _mods.output(dir, fallback)
+}
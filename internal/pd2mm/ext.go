@@ -58,6 +58,11 @@ func (c Cleaner) Clean(configs []Config, path int, update func() error) {
 func (c Cleaner) CleanWithError(configs []Config, path int, update func() error, errCh chan<- error) {
 	defer close(errCh)
 
+	if err := backupBeforeClean(configs, path); err != nil {
+		errCh <- err
+		return
+	}
+
 	for _, config := range configs {
 		for _, search := range config.Mods {
 			switch path {
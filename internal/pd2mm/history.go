@@ -0,0 +1,178 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package pd2mm
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hkmh223/pd2mm/common/filesystem"
+)
+
+const (
+	historyTimeFormat = "20060102-150405"
+	manifestName      = "manifest.json"
+)
+
+// newHistoryID creates a new directory under dir named after the current time and returns its name.
+// Entries created within the same second get a numbered suffix.
+func newHistoryID(dir string) (string, error) {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return "", err
+	}
+
+	base := time.Now().Format(historyTimeFormat)
+
+	for index := 0; ; index++ {
+		id := base
+		if index > 0 {
+			id += "-" + strconv.Itoa(index)
+		}
+
+		err := os.Mkdir(filepath.Join(dir, id), os.ModePerm)
+		if err == nil {
+			return id, nil
+		}
+
+		if !errors.Is(err, fs.ErrExist) {
+			return "", err
+		}
+	}
+}
+
+// discardHistory deletes the unfinished entry id under dir and returns err, so a failed entry leaves nothing behind.
+func discardHistory(dir, id string, err error) error {
+	return errors.Join(err, filesystem.DeleteBaseDirectory(filepath.Join(dir, id)))
+}
+
+// historyIDs returns the names of every entry under dir that has a manifest, oldest first.
+func historyIDs(dir string) ([]string, error) {
+	if !filesystem.Exists(dir) {
+		return nil, nil
+	}
+
+	directories, err := filesystem.GetTopDirectories(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+
+	for _, directory := range directories {
+		if filesystem.Exists(filepath.Join(dir, directory, manifestName)) {
+			ids = append(ids, directory)
+		}
+	}
+
+	slices.SortFunc(ids, compareHistoryIDs)
+
+	return ids, nil
+}
+
+// compareHistoryIDs orders ids by their time, then by their numbered suffix, so '-10' sorts after '-2'.
+func compareHistoryIDs(a, b string) int {
+	timeA, indexA := splitHistoryID(a)
+	timeB, indexB := splitHistoryID(b)
+
+	return cmp.Or(strings.Compare(timeA, timeB), cmp.Compare(indexA, indexB), strings.Compare(a, b))
+}
+
+// splitHistoryID returns the time of id and its numbered suffix, which is 0 if it has none.
+func splitHistoryID(id string) (string, int) {
+	if len(id) <= len(historyTimeFormat) {
+		return id, 0
+	}
+
+	index, err := strconv.Atoi(strings.TrimPrefix(id[len(historyTimeFormat):], "-"))
+	if err != nil {
+		return id, 0
+	}
+
+	return id[:len(historyTimeFormat)], index
+}
+
+// hasHistoryID returns true if id names an existing entry under dir. Anything else, such as a path, is rejected
+// before it is joined to dir.
+func hasHistoryID(dir, id string) (bool, error) {
+	ids, err := historyIDs(dir)
+	if err != nil {
+		return false, err
+	}
+
+	return slices.Contains(ids, id), nil
+}
+
+// pruneHistory deletes the oldest entries under dir until at most keep remain.
+// A keep value below one disables pruning.
+func pruneHistory(dir string, keep int) error {
+	if keep < 1 {
+		return nil
+	}
+
+	ids, err := historyIDs(dir)
+	if err != nil {
+		return err
+	}
+
+	for len(ids) > keep {
+		if err := filesystem.DeleteBaseDirectory(filepath.Join(dir, ids[0])); err != nil {
+			return err
+		}
+
+		ids = ids[1:]
+	}
+
+	return nil
+}
+
+// readManifest reads the manifest of the history entry id under dir.
+//
+//nolint:ireturn // reason: T should not have constraints.
+func readManifest[T any](dir, id string) (T, error) {
+	var manifest T
+
+	data, err := filesystem.ReadFile(filepath.Join(dir, id, manifestName))
+	if err != nil {
+		return manifest, err
+	}
+
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return manifest, err
+	}
+
+	return manifest, nil
+}
+
+// writeManifest writes the manifest of the history entry id under dir.
+func writeManifest(dir, id string, manifest any) error {
+	data, err := json.MarshalIndent(manifest, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	return filesystem.WriteFile(filepath.Join(dir, id, manifestName), data, 0o644)
+}
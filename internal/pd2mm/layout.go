@@ -0,0 +1,178 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package pd2mm
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/hkmh223/pd2mm/common/filesystem"
+	"github.com/hkmh223/pd2mm/common/logger"
+	"github.com/hkmh223/pd2mm/common/pattern"
+	"github.com/hkmh223/pd2mm/internal/data"
+	"github.com/hkmh223/pd2mm/internal/lang"
+	"github.com/hkmh223/pd2mm/internal/mod"
+)
+
+// layoutCheck holds the packaging mistakes found in the archive extracted to path.
+type layoutCheck struct {
+	name     string
+	path     string
+	problems []mod.LayoutProblem
+}
+
+// checkLayouts analyzes the layout of every archive extracted for the config before any rule processes it, with
+// -auto-fix the mistakes that can be corrected are fixed first, so the rules see the normalized layout.
+// Each Extract directory is analyzed once with the markers of every PathSearch sharing it, so a folder one of them
+// deploys is not moved by the fixes of another.
+func (c Config) checkLayouts() []layoutCheck {
+	var checks []layoutCheck
+
+	for _, group := range c.extractGroups() {
+		if len(group.markers) == 0 || !filesystem.Exists(group.extract) {
+			continue
+		}
+
+		directories, err := filesystem.GetTopDirectories(group.extract)
+		if err != nil {
+			logger.SharedLogger.Warn("failed to get directories", "path", group.extract, "err", err)
+			continue
+		}
+
+		for _, directory := range directories {
+			path := filepath.Join(group.extract, directory)
+			checks = append(checks, layoutCheck{name: directory, path: path, problems: checkLayout(directory, path, group.markers, group.junk)})
+		}
+	}
+
+	return checks
+}
+
+// reportLayouts adds the mistakes of checkLayouts to the SharedReport of the mod they are in. It runs once all rules
+// copied their files, as an archive without a mod folder is only reported if no rule deployed any of its files.
+func reportLayouts(checks []layoutCheck) {
+	for _, check := range checks {
+		for _, problem := range check.problems {
+			if errors.Is(problem.Err, mod.ErrMissingRoot) && _routes.routed(check.path) {
+				continue
+			}
+
+			logger.SharedLogger.Warn(lang.Lang("layoutProblemNotify"),
+				"archive", check.name, "path", problem.Path, "problem", problem.Err, "fix", problem.Fix)
+			SharedReport.AddWarning(_mods.id(filepath.Join(check.path, problem.Path), check.name), problem)
+		}
+	}
+}
+
+// checkLayout returns the packaging mistakes of the extracted archive at dir, fixing them first with -auto-fix.
+func checkLayout(name, dir string, markers, junk []string) []mod.LayoutProblem {
+	var (
+		problems []mod.LayoutProblem
+		err      error
+	)
+
+	if data.Flag.AutoFix {
+		var fixed []mod.LayoutProblem
+
+		fixed, problems, err = mod.FixLayout(dir, markers, junk)
+
+		for _, problem := range fixed {
+			logger.SharedLogger.Info(lang.Lang("layoutFixedNotify"), "archive", name, "path", problem.Path, "problem", problem.Err)
+		}
+	} else {
+		problems, err = mod.AnalyzeLayout(dir, markers, junk)
+	}
+
+	if err != nil {
+		logger.SharedLogger.Warn("failed to analyze layout", "archive", name, "err", err)
+		return nil
+	}
+
+	return problems
+}
+
+// extractGroup is an Extract directory with the markers and junk patterns of every PathSearch sharing it.
+type extractGroup struct {
+	extract string
+	markers []string
+	junk    []string
+}
+
+// extractGroups returns the Extract directories of the config in order, each once.
+func (c Config) extractGroups() []extractGroup {
+	var groups []extractGroup
+
+	for _, entry := range c.Mods {
+		search := PathSearch{PathSearch: &entry}
+		extract := absolute(search.Extract.Path)
+
+		index := slices.IndexFunc(groups, func(group extractGroup) bool { return group.extract == extract })
+		if index < 0 {
+			groups = append(groups, extractGroup{extract: extract, markers: nil, junk: nil})
+			index = len(groups) - 1
+		}
+
+		groups[index].markers = union(groups[index].markers, search.markers())
+		groups[index].junk = union(groups[index].junk, search.junk())
+	}
+
+	return groups
+}
+
+// union appends the items of values that slice does not have yet.
+func union(slice, values []string) []string {
+	for _, value := range values {
+		if !slices.Contains(slice, value) {
+			slice = append(slice, value)
+		}
+	}
+
+	return slice
+}
+
+// markers returns the names whose parent folder is a mod folder, the first element of every expected path.
+// Expected paths that are regular expressions have no fixed first element and are left out.
+func (search PathSearch) markers() []string {
+	var markers []string
+
+	for _, expect := range search.Expects {
+		if strings.HasPrefix(expect.Path, pattern.RegexPrefix) {
+			continue
+		}
+
+		raw, glob := strings.CutPrefix(expect.Path, pattern.GlobPrefix)
+
+		parts := slices.DeleteFunc(filesystem.ToNormalizedSlice(raw), func(part string) bool { return part == "" || glob && part == pattern.GlobStar })
+		if len(parts) == 0 {
+			continue
+		}
+
+		marker := parts[0]
+		if !glob {
+			// Markers are matched with path.Match, so plain names escape its wildcards to match themselves.
+			marker = strings.NewReplacer("*", `\*`, "?", `\?`, "[", `\[`).Replace(marker)
+		}
+
+		markers = append(markers, marker)
+	}
+
+	return markers
+}
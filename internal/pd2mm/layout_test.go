@@ -0,0 +1,128 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package pd2mm_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/hkmh223/pd2mm/internal/data"
+	"github.com/hkmh223/pd2mm/internal/mod"
+	"github.com/hkmh223/pd2mm/internal/pd2mm"
+)
+
+// writeFiles creates the files below dir, with the slash separated names as keys.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// warnings returns every warning of the SharedReport matching target.
+func warnings(target error) []error {
+	var result []error
+
+	for _, entry := range pd2mm.SharedReport.Mods {
+		result = append(result, slices.DeleteFunc(slices.Clone(entry.Warnings), func(err error) bool { return !errors.Is(err, target) })...)
+	}
+
+	return result
+}
+
+func TestLayoutSharedExtract(t *testing.T) { //nolint:paralleltest // reason: a run changes the working directory and shared state.
+	tests := map[string]struct {
+		files    map[string]string
+		autoFix  bool
+		exist    []string
+		notExist []string
+	}{
+		"override": {
+			files:    map[string]string{"MyMod/units/x.unit": ""},
+			autoFix:  false,
+			exist:    []string{"pd2mm/pd2/output/mod_overrides/MyMod/units/x.unit"},
+			notExist: nil,
+		},
+		"several mods": {
+			files: map[string]string{
+				"Pack/A/main.xml":     `<table name="A"></table>`,
+				"Pack/B/units/x.unit": "",
+			},
+			autoFix: true,
+			exist: []string{
+				"pd2mm/pd2/output/mod_overrides/A/main.xml",
+				"pd2mm/pd2/output/mod_overrides/B/units/x.unit",
+				"pd2mm/pd2/extract/mod_overrides/Pack/B/units/x.unit",
+			},
+			notExist: []string{
+				"pd2mm/pd2/output/mod_overrides/A/B",
+				"pd2mm/pd2/extract/mod_overrides/Pack/A/B",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+
+			autoFix := data.Flag.AutoFix
+			data.Flag.AutoFix = test.autoFix
+
+			t.Cleanup(func() { data.Flag.AutoFix = autoFix })
+
+			writeFiles(t, "pd2mm/pd2/extract/mod_overrides", test.files)
+
+			if err := os.MkdirAll("pd2mm/pd2/extract/mods", 0o755); err != nil {
+				t.Fatal(err)
+			}
+
+			config := data.Default()
+			if err := pd2mm.RunProcess(pd2mm.Config{Config: &config}); err != nil {
+				t.Fatal(err)
+			}
+
+			if missing := warnings(mod.ErrMissingRoot); len(missing) > 0 {
+				t.Fatalf("unexpected missing mod folder: %v", missing)
+			}
+
+			for _, path := range test.exist {
+				if _, err := os.Stat(path); err != nil {
+					t.Errorf("expected '%s' to exist: %v", path, err)
+				}
+			}
+
+			for _, path := range test.notExist {
+				if _, err := os.Stat(path); err == nil {
+					t.Errorf("expected '%s' to not exist", path)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package pd2mm
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/hkmh223/pd2mm/common/filesystem"
+	"github.com/hkmh223/pd2mm/common/lua"
+)
+
+type luaFile struct {
+	mod  string
+	path string
+	name string
+}
+
+// CheckLua parses every Lua file of the mods in the Output directory of search concurrently.
+// Syntax errors are added to the SharedReport as warnings of the mod the file belongs to.
+func (c Config) CheckLua(search PathSearch) error {
+	output, err := filesystem.FromCwd(search.Output.Path)
+	if err != nil {
+		return err
+	}
+
+	directories, err := filesystem.GetTopDirectories(output)
+	if err != nil {
+		return &MError{Header: "CheckLua", Message: "failed to get directories '" + search.Output.Path + "'", Err: err}
+	}
+
+	files := make(chan luaFile)
+
+	var wait sync.WaitGroup
+
+	for range runtime.NumCPU() {
+		wait.Add(1)
+
+		go func() {
+			defer wait.Done()
+
+			for file := range files {
+				if err := lua.CheckFile(file.path); err != nil {
+					SharedReport.AddWarning(file.mod, &MError{Header: "CheckLua", Message: file.name, Err: err})
+				}
+			}
+		}()
+	}
+
+	for _, directory := range directories {
+		root := filepath.Join(output, directory)
+
+		for _, file := range filesystem.GetFiles(root) {
+			if !strings.EqualFold(filesystem.GetFileExtension(file), ".lua") {
+				continue
+			}
+
+			name, err := filepath.Rel(root, file)
+			if err != nil {
+				name = file
+			}
+
+			files <- luaFile{mod: _mods.output(root, directory), path: file, name: filesystem.Normalize(name)}
+		}
+	}
+
+	close(files)
+	wait.Wait()
+
+	return nil
+}
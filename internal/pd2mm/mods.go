@@ -0,0 +1,197 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package pd2mm
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/hkmh223/pd2mm/common/filesystem"
+	"github.com/hkmh223/pd2mm/common/logger"
+	"github.com/hkmh223/pd2mm/internal/data"
+	"github.com/hkmh223/pd2mm/internal/mod"
+)
+
+var _mods = newModIndex() //nolint:gochecknoglobals // reason: filled while the mods of a run are processed.
+
+// modIndex maps the extracted folder of every mod, and the Output folders it was deployed to, to its identity.
+// Every report of a mod is keyed by that identity, such as 'Pack/MyMod', whichever folder the check looked at.
+type modIndex struct {
+	mu sync.Mutex
+
+	roots   map[string]string
+	outputs map[string]string
+}
+
+func newModIndex() *modIndex {
+	return &modIndex{roots: map[string]string{}, outputs: map[string]string{}} //nolint:exhaustruct // reason: zero mutex.
+}
+
+func (m *modIndex) reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.roots, m.outputs = map[string]string{}, map[string]string{}
+}
+
+// add records that the mod with the identity id was extracted to root.
+func (m *modIndex) add(root, id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.roots[absolute(root)] = id
+}
+
+// id returns the identity of the mod the extracted file or folder at path belongs to, or fallback if it belongs to
+// none, such as the loose files next to the mods of an archive with several mods.
+func (m *modIndex) id(path, fallback string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.find(absolute(path), fallback)
+}
+
+// deploy records that the mod src belongs to was copied to dest. Only the top folder of dest below output is kept,
+// which is what the checks of the Output directory see.
+func (m *modIndex) deploy(src, dest, output string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	output = absolute(output)
+
+	rel, ok := strings.CutPrefix(absolute(dest), output+"/")
+	if !ok {
+		return
+	}
+
+	top, _, _ := strings.Cut(rel, "/")
+
+	id := m.find(absolute(src), "")
+	if _, found := m.outputs[output+"/"+top]; !found && id != "" {
+		m.outputs[output+"/"+top] = id
+	}
+}
+
+// output returns the identity of the mod deployed to the Output folder dir, or fallback if none was.
+func (m *modIndex) output(dir, fallback string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if id, ok := m.outputs[absolute(dir)]; ok {
+		return id
+	}
+
+	return fallback
+}
+
+// find returns the identity of the innermost mod folder containing path.
+func (m *modIndex) find(path, fallback string) string {
+	for dir := path; ; dir = filepath.ToSlash(filepath.Dir(dir)) {
+		if id, ok := m.roots[dir]; ok {
+			return id
+		}
+
+		if parent := filepath.ToSlash(filepath.Dir(dir)); parent == dir {
+			return fallback
+		}
+	}
+}
+
+// modRoots returns the mod folders of the archive extracted to path that are deployed on their own.
+// An archive with at most one mod folder is deployed as a whole, so the Include rules see every file of it.
+func (search PathSearch) modRoots(path string) []string {
+	whole := []string{"."}
+
+	markers := search.markers()
+	if len(markers) == 0 {
+		return whole
+	}
+
+	dir, err := filesystem.FromCwd(path)
+	if err != nil {
+		logger.SharedLogger.Warn("failed to find mod folders", "path", path, "err", err)
+		return whole
+	}
+
+	roots, err := mod.Roots(dir, markers, search.junk())
+	if err != nil {
+		logger.SharedLogger.Warn("failed to find mod folders", "path", path, "err", err)
+		return whole
+	}
+
+	if len(roots) < 2 { //nolint:mnd // reason: a single mod is deployed as a whole.
+		return whole
+	}
+
+	return roots
+}
+
+// modIDs returns the identity of every mod extracted for the PathSearch.
+func (search PathSearch) modIDs() ([]string, error) {
+	cwd, err := filesystem.FromCwd(search.Extract.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !filesystem.Exists(cwd) {
+		return nil, nil
+	}
+
+	directories, err := filesystem.GetTopDirectories(cwd)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+
+	for _, directory := range directories {
+		for _, root := range search.modRoots(filepath.Join(search.Extract.Path, directory)) {
+			ids = append(ids, mod.RootID(directory, root))
+		}
+	}
+
+	return ids, nil
+}
+
+// modsCommand handles `mods list`.
+func modsCommand(args []string) error {
+	if args[0] != "list" {
+		return &MError{Header: "mods", Message: fmt.Sprintf("'%s', expected one of: list", args[0]), Err: ErrUnknownCommand}
+	}
+
+	configs, err := Configs(Flags{Flags: data.Flag})
+	if err != nil {
+		return err
+	}
+
+	for _, search := range searches(configs) {
+		ids, err := PathSearch{PathSearch: &search}.modIDs()
+		if err != nil {
+			return &MError{Header: "mods", Message: "failed to list mods of '" + search.Extract.Path + "'", Err: err}
+		}
+
+		for _, id := range ids {
+			logger.SharedLogger.Info(id, "mods", search.Mods, "disabled", search.IsDisabled(id))
+		}
+	}
+
+	return nil
+}
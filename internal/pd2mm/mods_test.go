@@ -0,0 +1,82 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package pd2mm_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hkmh223/pd2mm/internal/data"
+	"github.com/hkmh223/pd2mm/internal/pd2mm"
+)
+
+func TestProcessSeveralMods(t *testing.T) { //nolint:paralleltest // reason: a run changes the working directory and shared state.
+	t.Chdir(t.TempDir())
+
+	writeFiles(t, "pd2mm/pd2/extract/mods/Pack", map[string]string{
+		"A/mod.txt":  `{"name": "A"}`,
+		"A/main.lua": "",
+		"B/mod.txt":  `{"name": "B"}`,
+		"B/main.lua": "",
+		"readme.txt": "",
+	})
+
+	if err := os.MkdirAll("pd2mm/pd2/extract/mod_overrides", 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	config := data.Default()
+	config.Mods[0].Disabled = []string{"Pack/B"}
+
+	if err := pd2mm.RunProcess(pd2mm.Config{Config: &config}); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := map[string]string{
+		"pd2mm/pd2/extract/mods/Pack/A/main.lua": "Pack/A",
+		"pd2mm/pd2/extract/mods/Pack/B":          "Pack/B",
+		"pd2mm/pd2/extract/mods/Pack/readme.txt": "Pack",
+	}
+
+	for path, expected := range ids {
+		if id := pd2mm.ModID(path, "Pack"); id != expected {
+			t.Errorf("expected '%s' to belong to '%s', got '%s'", path, expected, id)
+		}
+	}
+
+	if _, err := os.Stat("pd2mm/pd2/output/mods/A/main.lua"); err != nil {
+		t.Errorf("expected the enabled mod to be deployed: %v", err)
+	}
+
+	if _, err := os.Stat("pd2mm/pd2/output/mods/B"); err == nil {
+		t.Error("expected the disabled mod to not be deployed")
+	}
+
+	if entry, ok := pd2mm.SharedReport.Mods["Pack/B"]; !ok || !entry.Disabled {
+		t.Errorf("expected 'Pack/B' to be reported as disabled: %+v", entry)
+	}
+
+	if id := pd2mm.ModOutput("pd2mm/pd2/output/mods/A", "A"); id != "Pack/A" {
+		t.Errorf("expected the output folder 'A' to be keyed by 'Pack/A', got '%s'", id)
+	}
+
+	if id := pd2mm.ModOutput("pd2mm/pd2/output/mods/B", "B"); id != "B" {
+		t.Errorf("expected no mod to be deployed to 'B', got '%s'", id)
+	}
+}
@@ -0,0 +1,110 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package pd2mm
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+
+	"github.com/hkmh223/pd2mm/common/diesel"
+	"github.com/hkmh223/pd2mm/common/filesystem"
+	"github.com/hkmh223/pd2mm/internal/mod"
+)
+
+const ModOverrides = "mod_overrides"
+
+var ErrMissingAsset = errors.New("asset does not exist in the game")
+
+// isModOverrides returns true if the Output directory of search is a mod_overrides directory.
+func isModOverrides(search PathSearch) bool {
+	return strings.EqualFold(filepath.Base(filepath.Clean(search.Output.Path)), ModOverrides)
+}
+
+// CheckOverrides looks up every file of the mods in the mod_overrides Output directories of the config in the bundle
+// database at name. The database is read once and each directory is checked once, even if several Mods share it.
+// Files replacing assets the game does not contain are added to the SharedReport as warnings.
+// Only extensions known to the database are checked, and mods with an add.xml or main.xml are skipped
+// since they can add new assets.
+func (c Config) CheckOverrides(name string) error {
+	var db *diesel.BundleDB
+
+	visited := map[string]bool{}
+
+	for _, search := range c.Mods {
+		if !isModOverrides(PathSearch{PathSearch: &search}) {
+			continue
+		}
+
+		output, err := filesystem.FromCwd(search.Output.Path)
+		if err != nil {
+			return err
+		}
+
+		if visited[output] || !filesystem.Exists(output) {
+			continue
+		}
+
+		visited[output] = true
+
+		directories, err := filesystem.GetTopDirectories(output)
+		if err != nil {
+			return &MError{Header: "CheckOverrides", Message: "failed to get directories '" + search.Output.Path + "'", Err: err}
+		}
+
+		if db == nil {
+			if db, err = diesel.ReadBundleDB(name); err != nil {
+				return &MError{Header: "CheckOverrides", Message: "failed to read bundle database '" + name + "'", Err: err}
+			}
+		}
+
+		checkOverrides(db, output, directories)
+	}
+
+	return nil
+}
+
+// checkOverrides adds a warning for every file of the mods in directories of output that db does not contain.
+func checkOverrides(db *diesel.BundleDB, output string, directories []string) {
+	for _, directory := range directories {
+		root := filepath.Join(output, directory)
+		id := _mods.output(root, directory)
+
+		if filesystem.ExistsFold(root, mod.AddXML) || filesystem.ExistsFold(root, mod.MainXML) {
+			continue
+		}
+
+		for _, file := range filesystem.GetFiles(root) {
+			ext := filesystem.GetFileExtension(file)
+			if ext == "" || !db.HasExtension(ext) {
+				continue
+			}
+
+			rel, err := filepath.Rel(root, file)
+			if err != nil {
+				continue
+			}
+
+			asset := filesystem.Normalize(strings.TrimSuffix(rel, ext))
+			if !db.Contains(asset, ext) {
+				SharedReport.AddWarning(id, &MError{Header: "CheckOverrides", Message: filesystem.Normalize(rel), Err: ErrMissingAsset})
+			}
+		}
+	}
+}
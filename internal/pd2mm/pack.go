@@ -0,0 +1,101 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package pd2mm
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hkmh223/pd2mm/common/filesystem"
+	"github.com/hkmh223/pd2mm/common/logger"
+	"github.com/hkmh223/pd2mm/common/zip"
+	"github.com/hkmh223/pd2mm/internal/lang"
+	"github.com/hkmh223/pd2mm/internal/mod"
+)
+
+var (
+	ErrNotDirectory = errors.New("path is not a directory")
+	ErrInvalidMod   = errors.New("mod failed validation")
+)
+
+// Pack validates the mod at src and writes a reproducible zip archive of it to dest.
+// Junk files are left out and every entry is stored under the name of the mod directory.
+func Pack(src, dest string) error {
+	src = filepath.Clean(src)
+
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		return &MError{Header: "Pack", Message: fmt.Sprintf("cannot pack '%s'", src), Err: ErrNotDirectory}
+	}
+
+	if problems := mod.Validate(src); len(problems) != 0 {
+		for _, problem := range problems {
+			logger.SharedLogger.Error(lang.Lang("validationNotify"), "path", src, "err", problem)
+		}
+
+		return &MError{Header: "Pack", Message: fmt.Sprintf("found %d problem(s) in '%s'", len(problems), src), Err: ErrInvalidMod}
+	}
+
+	var files []string
+
+	for _, file := range filesystem.GetFiles(src) {
+		relative, err := filepath.Rel(src, file)
+		if err != nil {
+			return err
+		}
+
+		if mod.IsJunk(relative, mod.DefaultJunk()) {
+			logger.SharedLogger.Info(lang.Lang("skippingNotify"), "path", file)
+			continue
+		}
+
+		files = append(files, file)
+	}
+
+	return zip.WithFiles(src, dest, filepath.Base(src), files, zip.DefaultZipMessenger())
+}
+
+// packCommand handles `pack <dir> [archive]`.
+func packCommand(args []string) error {
+	src := args[0]
+	dest := filepath.Base(filepath.Clean(src)) + ".zip"
+
+	if len(args) > 1 {
+		dest = args[1]
+	}
+
+	if !strings.HasSuffix(strings.ToLower(dest), ".zip") {
+		dest += ".zip"
+	}
+
+	if err := Pack(src, dest); err != nil {
+		return err
+	}
+
+	logger.SharedLogger.Info(lang.Lang("packedNotify"), "source", src, "destination", dest)
+
+	return nil
+}
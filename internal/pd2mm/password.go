@@ -0,0 +1,47 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package pd2mm
+
+import (
+	"bufio"
+	"os"
+	"strings"
+
+	"github.com/hkmh223/pd2mm/common/logger"
+	"github.com/hkmh223/pd2mm/internal/io"
+	"github.com/hkmh223/pd2mm/internal/lang"
+)
+
+// setupPasswordPrompt makes encrypted archives without a configured password ask for it on stdin.
+func setupPasswordPrompt() {
+	stdin := bufio.NewReader(os.Stdin)
+
+	io.PasswordPrompt = func(src string) (string, bool) {
+		logger.SharedLogger.Warn(lang.Lang("passwordPrompt"), "source", src)
+
+		line, err := stdin.ReadString('\n')
+		if err != nil && line == "" {
+			return "", false
+		}
+
+		password := strings.TrimRight(line, "\r\n")
+
+		return password, password != ""
+	}
+}
@@ -0,0 +1,94 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package pd2mm
+
+import (
+	"strings"
+
+	"github.com/hkmh223/pd2mm/common/filesystem"
+	"github.com/hkmh223/pd2mm/common/pattern"
+	"github.com/hkmh223/pd2mm/common/util"
+)
+
+// rulePattern returns the compiled rule path if it uses the glob or regular expression syntax.
+// Plain rule paths return false and keep matching the way they always did.
+func rulePattern(raw string) (pattern.Pattern, bool) {
+	if pattern.IsPlain(raw) {
+		return pattern.Pattern{}, false //nolint:exhaustruct // reason: not a pattern.
+	}
+
+	// Patterns are validated when the config is read.
+	compiled, err := pattern.Compile(raw)
+
+	return compiled, err == nil
+}
+
+// archiveParts returns the segments of path below the folder its archive was extracted to.
+// Patterns match these, so ^ anchors a regular expression at the top of the archive.
+func (search PathSearch) archiveParts(path string) []string {
+	rel, ok := strings.CutPrefix(absolute(path), absolute(search.Extract.Path)+"/")
+	if !ok {
+		return nil
+	}
+
+	if _, rel, _ = strings.Cut(rel, "/"); rel == "" {
+		return nil
+	}
+
+	return filesystem.ToNormalizedSlice(rel)
+}
+
+// includes returns true if the include path raw matches source.
+func (search PathSearch) includes(source, raw string) bool {
+	if compiled, ok := rulePattern(raw); ok {
+		return compiled.Match(search.archiveParts(source))
+	}
+
+	return strings.Contains(source, search.FormatString(raw))
+}
+
+// renames returns true if the rename path raw matches src.
+func (search PathSearch) renames(src, raw string) bool {
+	if compiled, ok := rulePattern(raw); ok {
+		return compiled.Match(search.archiveParts(src))
+	}
+
+	return util.ContainsSubslice(strings.Split(src, "/"), search.FormatSlice(filesystem.ToNormalizedSlice(raw)))
+}
+
+// renameDestination replaces from by to in dest. Patterns match the segments of dest below the output directory,
+// a regular expression may use its capture groups in to, such as $1 or ${name}.
+func (search PathSearch) renameDestination(dest, from, to string) string {
+	compiled, ok := rulePattern(from)
+	if !ok {
+		fromFmt := search.FormatSlice(filesystem.ToNormalizedSlice(from))
+		toFmt := search.FormatSlice(filesystem.ToNormalizedSlice(to))
+
+		return strings.Join(util.ReplaceSubslice(strings.Split(dest, "/"), fromFmt, toFmt), "/")
+	}
+
+	output := absolute(search.Output.Path)
+
+	rel, found := strings.CutPrefix(absolute(dest), output+"/")
+	if !found {
+		return dest
+	}
+
+	return output + "/" + strings.Join(compiled.Replace(strings.Split(rel, "/"), to), "/")
+}
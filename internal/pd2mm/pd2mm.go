@@ -19,6 +19,7 @@
 package pd2mm
 
 import (
+	"path/filepath"
 	"slices"
 
 	"github.com/hkmh223/pd2mm/common/filesystem"
@@ -27,7 +28,7 @@ import (
 	"github.com/hkmh223/pd2mm/internal/lang"
 )
 
-// Setup creates the default config if it does not exist.
+// Setup creates the default config if it does not exist and writes the schema it references.
 func Setup() {
 	path, err := filesystem.FromCwd(lang.Lang("defaultConfigPath"))
 	if err != nil {
@@ -42,6 +43,10 @@ func Setup() {
 			return
 		}
 	}
+
+	if err := data.WriteSchema(lang.Lang("defaultSchemaPath")); err != nil {
+		logger.SharedLogger.Error("failed to write schema", "err", err)
+	}
 }
 
 // Start starts the program.
@@ -64,6 +69,11 @@ func ConfigNames(flags Flags) ([]string, error) {
 		}
 
 		for _, file := range files {
+			// The schema is written next to the configs.
+			if file == filepath.Base(lang.Lang("defaultSchemaPath")) {
+				continue
+			}
+
 			path, err := filesystem.FromCwd(lang.Lang("programName"), file)
 			if err != nil {
 				return nil, err
@@ -88,9 +98,13 @@ func Configs(flags Flags) ([]Config, error) {
 	}
 
 	for _, entry := range entries {
-		if c, err := data.Read(entry); err == nil {
-			configs = append(configs, Config{Config: &c})
+		c, err := data.Read(entry)
+		if err != nil {
+			logger.SharedLogger.Warn(lang.Lang("configSkippedNotify"), "err", err)
+			continue
 		}
+
+		configs = append(configs, Config{Config: &c})
 	}
 
 	return configs, nil
@@ -0,0 +1,190 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package pd2mm
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+
+	"github.com/hkmh223/pd2mm/common/logger"
+	"github.com/hkmh223/pd2mm/internal/data"
+	"github.com/hkmh223/pd2mm/internal/lang"
+)
+
+var SharedReport = NewReport() //nolint:gochecknoglobals // reason: filled by every stage of a run.
+
+type Report struct {
+	mu sync.Mutex
+
+	Mods map[string]*ModReport
+}
+
+type ModReport struct {
+	Name     string
+	Errors   []error
+	Warnings []error
+	// Filtered is the number of junk files left out of the mod.
+	Filtered int
+	// Disabled is true if the mod was not deployed because it is disabled in the config.
+	Disabled bool
+	// Unrouted are the files of the archive that no rule copied, relative to the archive.
+	Unrouted []string
+}
+
+// NewReport creates a new, empty Report.
+func NewReport() *Report {
+	return &Report{ //nolint:exhaustruct // reason: value is set
+		Mods: map[string]*ModReport{},
+	}
+}
+
+// Reset removes every entry from the report.
+func (r *Report) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.Mods = map[string]*ModReport{}
+}
+
+// AddError adds an error to the entry of the named mod.
+func (r *Report) AddError(name string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry := r.entry(name)
+	entry.Errors = append(entry.Errors, err)
+}
+
+// AddWarning adds a warning to the entry of the named mod.
+func (r *Report) AddWarning(name string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry := r.entry(name)
+	entry.Warnings = append(entry.Warnings, err)
+}
+
+// AddFiltered adds count junk files to the entry of the named mod.
+func (r *Report) AddFiltered(name string, count int) {
+	if count == 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entry(name).Filtered += count
+}
+
+// SetDisabled marks the named mod as disabled.
+func (r *Report) SetDisabled(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entry(name).Disabled = true
+}
+
+// AddUnrouted adds files that no rule copied to the entry of the named archive.
+func (r *Report) AddUnrouted(name string, files []string) {
+	if len(files) == 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry := r.entry(name)
+	entry.Unrouted = append(entry.Unrouted, files...)
+}
+
+// Names returns the sorted names of the entries with an error matching target.
+func (r *Report) Names(target error) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var names []string
+
+	for name, entry := range r.Mods {
+		if slices.ContainsFunc(entry.Errors, func(err error) bool { return errors.Is(err, target) }) {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// Log writes every entry of the report to the SharedLogger, sorted by mod name.
+func (r *Report) Log() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	filtered, disabled, unrouted := 0, 0, 0
+
+	for _, entry := range r.Mods {
+		filtered += entry.Filtered
+		unrouted += len(entry.Unrouted)
+
+		if entry.Disabled {
+			disabled++
+		}
+	}
+
+	logger.SharedLogger.Info(lang.Lang("reportNotify"), "mods", len(r.Mods), "disabled", disabled, "filtered", filtered, "unrouted", unrouted)
+
+	for _, name := range slices.Sorted(maps.Keys(r.Mods)) {
+		entry := r.Mods[name]
+
+		if entry.Disabled {
+			logger.SharedLogger.Info(entry.Name, "disabled", true)
+		}
+
+		if entry.Filtered > 0 {
+			logger.SharedLogger.Info(entry.Name, "filtered", entry.Filtered)
+		}
+
+		if len(entry.Unrouted) > 0 && !data.Flag.ListUnrouted {
+			logger.SharedLogger.Warn(entry.Name, "unrouted", len(entry.Unrouted))
+		} else {
+			for _, file := range entry.Unrouted {
+				logger.SharedLogger.Warn(entry.Name, "unrouted", file)
+			}
+		}
+
+		for _, err := range entry.Errors {
+			logger.SharedLogger.Error(entry.Name, "err", err)
+		}
+
+		for _, err := range entry.Warnings {
+			logger.SharedLogger.Warn(entry.Name, "warn", err)
+		}
+	}
+}
+
+// entry returns the entry of the named mod, creating it if needed.
+func (r *Report) entry(name string) *ModReport {
+	if _, ok := r.Mods[name]; !ok {
+		r.Mods[name] = &ModReport{Name: name, Errors: nil, Warnings: nil, Filtered: 0, Disabled: false, Unrouted: nil}
+	}
+
+	return r.Mods[name]
+}
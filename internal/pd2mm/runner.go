@@ -19,11 +19,13 @@
 package pd2mm
 
 import (
+	"strings"
 	"sync"
 	"sync/atomic"
 
 	"github.com/hkmh223/pd2mm/common/benchmark"
 	"github.com/hkmh223/pd2mm/common/logger"
+	"github.com/hkmh223/pd2mm/internal/data"
 	"github.com/hkmh223/pd2mm/internal/io"
 	"github.com/hkmh223/pd2mm/internal/lang"
 )
@@ -85,10 +87,15 @@ func (r *Runner) Run(flags Flags, configs []Config) {
 func (f Flags) RunWithError(configs []Config, errCh chan<- error) {
 	defer close(errCh)
 
+	SharedReport.Reset()
+	_routes.reset()
+	_mods.reset()
+	SharedTrace.Reset()
+	defer SharedReport.Log()
+
 	for _, config := range configs {
 		err := benchmark.Timer(func() error {
-			f.runner(config)
-			return nil
+			return f.runner(config)
 		}, "Start", func(methodName, elapsedTime string) {
 			logger.SharedLogger.Infof("%s took %s", methodName, elapsedTime)
 		})
@@ -98,25 +105,57 @@ func (f Flags) RunWithError(configs []Config, errCh chan<- error) {
 			return
 		}
 	}
+
+	if f.Trace {
+		if err := SharedTrace.Write(TraceFile()); err != nil {
+			logger.SharedLogger.Error("failed to write trace", "path", TraceFile(), "err", err)
+		}
+	}
+
+	if f.Strict {
+		if names := SharedReport.Names(ErrNothingDeployed); len(names) > 0 {
+			errCh <- &MError{Header: "strict", Message: "nothing deployed from " + strings.Join(names, ", "), Err: ErrNothingDeployed}
+		}
+	}
 }
 
-// runner starts the extraction and processing of mods.
-func (f Flags) runner(config Config) {
+// runner starts the extraction and processing of mods. It fails if the saves could not be backed up before the
+// Output directories are cleaned, since nothing was deployed then.
+func (f Flags) runner(config Config) error {
 	SharedCleaner.Clean([]Config{config}, Extract, func() error {
 		logger.SharedLogger.Info(lang.Lang("doneExtractCleanerNotify"))
 		return runExtract(f, config)
 	})
 
-	SharedCleaner.Clean([]Config{config}, Output, func() error {
+	if f.Snapshot {
+		if _, err := TakeSnapshot([]Config{config}, f.SnapshotRetention); err != nil {
+			logger.SharedLogger.Error("failed to take snapshot", "err", err)
+		}
+	}
+
+	errCh := make(chan error, 1)
+	SharedCleaner.CleanWithError([]Config{config}, Output, func() error {
 		logger.SharedLogger.Info(lang.Lang("doneOutputCleanerNotify"))
 		return runProcess(config)
-	})
+	}, errCh)
+
+	return <-errCh
 }
 
 // runExtract extracts the contents of an archive to a specified directory.
 func runExtract(f Flags, config Config) error {
 	for _, search := range config.Mods {
-		if err := io.Extract(*f.Flags, search); err != nil {
+		problems, err := io.Extract(*f.Flags, search)
+
+		for _, problem := range problems {
+			if problem.Rejected {
+				SharedReport.AddError(io.ArchiveName(problem.Archive), problem.Err)
+			} else {
+				SharedReport.AddWarning(io.ArchiveName(problem.Archive), problem.Err)
+			}
+		}
+
+		if err != nil {
 			return err
 		}
 	}
@@ -126,12 +165,30 @@ func runExtract(f Flags, config Config) error {
 
 // runProcess processes the extracted mods.
 func runProcess(config Config) error {
+	layouts := config.checkLayouts()
+
 	for _, search := range config.Mods {
 		if err := config.Process(PathSearch{PathSearch: &search}); err != nil {
 			logger.SharedLogger.Error("failed to process mods", "err", err)
-			continue
 		}
 	}
 
+	if err := config.ValidateMods(); err != nil {
+		logger.SharedLogger.Error("failed to validate mods", "err", err)
+	}
+
+	reportLayouts(layouts)
+	config.reportUnrouted()
+
+	if data.Flag.BundleDB != "" {
+		if err := config.CheckOverrides(data.Flag.BundleDB); err != nil {
+			logger.SharedLogger.Error("failed to check mod_overrides", "err", err)
+		}
+	}
+
+	if err := config.CheckConflicts(); err != nil {
+		logger.SharedLogger.Error("failed to check conflicts", "err", err)
+	}
+
 	return nil
 }
@@ -0,0 +1,222 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package pd2mm
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/hkmh223/pd2mm/common/filesystem"
+	"github.com/hkmh223/pd2mm/common/logger"
+	"github.com/hkmh223/pd2mm/common/zip"
+	"github.com/hkmh223/pd2mm/internal/data"
+	"github.com/hkmh223/pd2mm/internal/lang"
+)
+
+var ErrSnapshotNotFound = errors.New("snapshot not found")
+
+type Snapshot struct {
+	ID      string          `json:"id"`
+	Created time.Time       `json:"created"`
+	Entries []SnapshotEntry `json:"entries"`
+}
+
+type SnapshotEntry struct {
+	Archive string          `json:"archive"`
+	Search  data.PathSearch `json:"search"`
+}
+
+// SnapshotDirectory returns the directory snapshots are stored in.
+func SnapshotDirectory() string {
+	return filesystem.Combine(lang.Lang("programName"), "snapshots")
+}
+
+// TakeSnapshot archives the Export directory of every PathSearch in configs, then prunes snapshots beyond retention.
+// Files protected by ExcludeClean are left out, they are not touched by a deploy or a restore.
+func TakeSnapshot(configs []Config, retention int) (Snapshot, error) {
+	dir := SnapshotDirectory()
+
+	id, err := newHistoryID(dir)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	snapshot := Snapshot{ID: id, Created: time.Now(), Entries: []SnapshotEntry{}}
+	seen := map[string]bool{}
+
+	for _, config := range configs {
+		for _, search := range config.Mods {
+			if search.Export.Path == "" || seen[search.Export.Path] {
+				continue
+			}
+
+			seen[search.Export.Path] = true
+
+			entry, err := snapshotExport(search, filepath.Join(dir, id), len(snapshot.Entries))
+			if err != nil {
+				return Snapshot{}, discardHistory(dir, id, err)
+			}
+
+			snapshot.Entries = append(snapshot.Entries, entry)
+		}
+	}
+
+	if len(snapshot.Entries) == 0 {
+		logger.SharedLogger.Warn(lang.Lang("snapshotEmptyNotify"))
+		return snapshot, filesystem.DeleteBaseDirectory(filepath.Join(dir, id))
+	}
+
+	if err := writeManifest(dir, id, snapshot); err != nil {
+		return Snapshot{}, discardHistory(dir, id, err)
+	}
+
+	logger.SharedLogger.Info(lang.Lang("snapshotNotify"), "id", id, "entries", len(snapshot.Entries))
+
+	return snapshot, pruneHistory(dir, retention)
+}
+
+// snapshotExport archives the Export directory of search into dir.
+func snapshotExport(search data.PathSearch, dir string, index int) (SnapshotEntry, error) {
+	export, err := filesystem.FromCwd(search.Export.Path)
+	if err != nil {
+		return SnapshotEntry{}, err
+	}
+
+	var files []string
+
+	for _, file := range filesystem.GetFiles(export) {
+		if !search.IsExcluded(file, search.Export) {
+			files = append(files, file)
+		}
+	}
+
+	archive := strconv.Itoa(index) + ".zip"
+	if err := zip.WithFiles(export, filepath.Join(dir, archive), "", files, zip.Messenger{AddedFile: func(string) {}}); err != nil {
+		return SnapshotEntry{}, &MError{Header: "TakeSnapshot", Message: fmt.Sprintf("failed to archive '%s'", export), Err: err}
+	}
+
+	return SnapshotEntry{Archive: archive, Search: search}, nil
+}
+
+// Snapshots returns every stored snapshot, oldest first.
+func Snapshots() ([]Snapshot, error) {
+	ids, err := historyIDs(SnapshotDirectory())
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]Snapshot, 0, len(ids))
+
+	for _, id := range ids {
+		snapshot, err := readManifest[Snapshot](SnapshotDirectory(), id)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots, nil
+}
+
+// RestoreSnapshot cleans every Export directory recorded in the snapshot id and extracts its archived state.
+func RestoreSnapshot(id string) error {
+	dir := SnapshotDirectory()
+
+	if found, err := hasHistoryID(dir, id); err != nil || !found {
+		return &MError{Header: "RestoreSnapshot", Message: fmt.Sprintf("'%s'", id), Err: errors.Join(ErrSnapshotNotFound, err)}
+	}
+
+	snapshot, err := readManifest[Snapshot](dir, id)
+	if err != nil {
+		return err
+	}
+
+	searches := make([]data.PathSearch, 0, len(snapshot.Entries))
+	for _, entry := range snapshot.Entries {
+		searches = append(searches, entry.Search)
+	}
+
+	if _, err := TakeBackup(searches, data.Flag.BackupRetention); err != nil {
+		return err
+	}
+
+	for _, entry := range snapshot.Entries {
+		logger.SharedLogger.Info(lang.Lang("restoreNotify"), "id", id, "path", entry.Search.Export.Path)
+
+		errCh := make(chan error, 1)
+		go entry.Search.CleanWithError(entry.Search.Export, errCh)
+
+		for err := range errCh {
+			if err != nil {
+				logger.SharedLogger.Errorf("%s %v", lang.Lang("errorNotify"), err)
+			}
+		}
+
+		export, err := filesystem.FromCwd(entry.Search.Export.Path)
+		if err != nil {
+			return err
+		}
+
+		if err := zip.UnzipByPrefixWithMessenger(filepath.Join(dir, id, entry.Archive), export, "", zip.Messenger{AddedFile: func(string) {}}); err != nil {
+			return &MError{Header: "RestoreSnapshot", Message: fmt.Sprintf("failed to restore '%s'", export), Err: err}
+		}
+	}
+
+	return nil
+}
+
+// snapshotCommand handles `snapshot create`, `snapshot list` and `snapshot restore <id>`.
+func snapshotCommand(args []string) error {
+	switch args[0] {
+	case "create":
+		configs, err := Configs(Flags{Flags: data.Flag})
+		if err != nil {
+			return err
+		}
+
+		_, err = TakeSnapshot(configs, data.Flag.SnapshotRetention)
+
+		return err
+	case "list":
+		snapshots, err := Snapshots()
+		if err != nil {
+			return err
+		}
+
+		for _, snapshot := range snapshots {
+			for _, entry := range snapshot.Entries {
+				logger.SharedLogger.Info(snapshot.ID, "created", snapshot.Created.Format(time.DateTime), "path", entry.Search.Export.Path)
+			}
+		}
+
+		return nil
+	case "restore":
+		if len(args) < 2 { //nolint:mnd // reason: restore requires an id.
+			return &MError{Header: "snapshot", Message: "usage: snapshot restore <id>", Err: ErrMissingArguments}
+		}
+
+		return RestoreSnapshot(args[1])
+	}
+
+	return &MError{Header: "snapshot", Message: fmt.Sprintf("'%s', expected one of: create, list, restore", args[0]), Err: ErrUnknownCommand}
+}
@@ -0,0 +1,209 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package pd2mm
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hkmh223/pd2mm/common/filesystem"
+	"github.com/hkmh223/pd2mm/common/logger"
+	"github.com/hkmh223/pd2mm/internal/data"
+	"github.com/hkmh223/pd2mm/internal/lang"
+	"github.com/hkmh223/pd2mm/internal/mod"
+)
+
+var ErrNotTraced = errors.New("no traced copy wrote the path, deploy with -trace first")
+
+// traceExport is the rule of the copy from an Output directory to its Export directory.
+const traceExport = "export"
+
+var SharedTrace = NewTracer() //nolint:gochecknoglobals // reason: filled by every copy of a run.
+
+// Trace records why every copy of a run happened. It is written with -trace and read by the explain command.
+type Trace struct {
+	Created time.Time   `json:"created"`
+	Copies  []TraceCopy `json:"copies"`
+}
+
+// TraceCopy is a single copy, the rule that caused it and the steps that computed its destination.
+type TraceCopy struct {
+	// Search is the mods directory of the PathSearch the rule belongs to.
+	Search string `json:"search"`
+	// Rule is include, expects, copy or export.
+	Rule        string   `json:"rule"`
+	Pattern     string   `json:"pattern"`
+	Source      string   `json:"source"`
+	Destination string   `json:"destination"`
+	Steps       []string `json:"steps"`
+	// Junk are the patterns of the files the copy left out.
+	Junk []string `json:"junk,omitempty"`
+}
+
+type Tracer struct {
+	mu sync.Mutex
+
+	trace Trace
+}
+
+// NewTracer creates a new, empty Tracer.
+func NewTracer() *Tracer {
+	return &Tracer{ //nolint:exhaustruct // reason: value is set
+		trace: Trace{Created: time.Now(), Copies: nil},
+	}
+}
+
+// TraceFile returns the file the trace of the last run is written to.
+func TraceFile() string {
+	return filesystem.Combine(lang.Lang("programName"), "trace", "trace.json")
+}
+
+// Reset removes every copy from the trace.
+func (t *Tracer) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.trace = Trace{Created: time.Now(), Copies: nil}
+}
+
+// Add records the copy of src to dest if -trace is set.
+func (t *Tracer) Add(search PathSearch, src, dest string, copy TraceCopy) {
+	if !data.Flag.Trace {
+		return
+	}
+
+	copy.Search, copy.Source, copy.Destination = search.Mods, absolute(src), absolute(dest)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.trace.Copies = append(t.trace.Copies, copy)
+}
+
+// Write writes the trace to path as JSON.
+func (t *Tracer) Write(path string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	content, err := json.MarshalIndent(t.trace, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+
+	return filesystem.WriteFile(path, content, 0o644) //nolint:mnd // reason: file permission.
+}
+
+// ReadTrace reads the trace written to path.
+func ReadTrace(path string) (Trace, error) {
+	var trace Trace
+
+	content, err := filesystem.ReadFile(path)
+	if err != nil {
+		return trace, err
+	}
+
+	if err := json.Unmarshal(content, &trace); err != nil {
+		return trace, err
+	}
+
+	return trace, nil
+}
+
+// Explain returns every copy that wrote path, in the order they happened, with Source and Destination
+// narrowed down to path. The last copy decides the content of a file. A path in an Export directory is also
+// explained by the copies that wrote the Output directory it was exported from.
+func (trace Trace) Explain(path string) ([]TraceCopy, error) {
+	path = absolute(path)
+
+	copies := trace.explain(path, true)
+	if len(copies) == 0 {
+		return nil, &MError{Header: "explain", Message: "'" + path + "'", Err: ErrNotTraced}
+	}
+
+	return copies, nil
+}
+
+// explain returns the copies that wrote path, following export copies back to their Output directory if export is set.
+// Copies that left path out as junk did not write it.
+func (trace Trace) explain(path string, export bool) []TraceCopy {
+	var copies []TraceCopy
+
+	for _, copy := range trace.Copies {
+		rel, ok := strings.CutPrefix(path, copy.Destination)
+		if !ok || (rel != "" && !strings.HasPrefix(rel, "/")) {
+			continue
+		}
+
+		if mod.IsJunk(filepath.Base(copy.Source)+rel, copy.Junk) {
+			continue
+		}
+
+		copy.Source += rel
+		copy.Destination = path
+
+		if copy.Rule == traceExport {
+			if !export {
+				continue
+			}
+
+			copies = append(copies, trace.explain(copy.Source, false)...)
+		}
+
+		copies = append(copies, copy)
+	}
+
+	return copies
+}
+
+// newTraceCopy creates a TraceCopy of rule, the source and destination are set when it is added.
+func newTraceCopy(rule, pattern string, steps ...string) TraceCopy {
+	return TraceCopy{Search: "", Rule: rule, Pattern: pattern, Source: "", Destination: "", Steps: steps, Junk: nil}
+}
+
+// explainCommand handles `explain <output-path>`.
+func explainCommand(args []string) error {
+	trace, err := ReadTrace(TraceFile())
+	if err != nil {
+		return &MError{Header: "explain", Message: "failed to read '" + TraceFile() + "'", Err: errors.Join(ErrNotTraced, err)}
+	}
+
+	copies, err := trace.Explain(args[0])
+	if err != nil {
+		return err
+	}
+
+	for _, copy := range copies {
+		logger.SharedLogger.Info(copy.Destination, "source", copy.Source, "rule", copy.Rule, "pattern", copy.Pattern, "search", copy.Search)
+
+		for _, step := range copy.Steps {
+			logger.SharedLogger.Info("  " + step)
+		}
+	}
+
+	return nil
+}
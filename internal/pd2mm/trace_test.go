@@ -0,0 +1,99 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package pd2mm_test
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/hkmh223/pd2mm/internal/mod"
+	"github.com/hkmh223/pd2mm/internal/pd2mm"
+)
+
+func TestTraceExplain(t *testing.T) {
+	t.Parallel()
+
+	dir := filepath.ToSlash(t.TempDir())
+	extract, output, export := dir+"/extract", dir+"/output", dir+"/export"
+
+	trace := pd2mm.Trace{ //nolint:exhaustruct // reason: only the copies are explained.
+		Copies: []pd2mm.TraceCopy{
+			{Rule: "expects", Source: extract + "/Mod", Destination: output + "/Mod", Junk: mod.DefaultJunk()},
+			{Rule: "expects", Source: extract + "/Mod2", Destination: output + "/Mod2"},
+			{Rule: "export", Source: output, Destination: export},
+		},
+	}
+
+	tests := map[string]struct {
+		path         string
+		rules        []string
+		sources      []string
+		destinations []string
+	}{
+		"export": {
+			path:         export + "/Mod/main.lua",
+			rules:        []string{"expects", "export"},
+			sources:      []string{extract + "/Mod/main.lua", output + "/Mod/main.lua"},
+			destinations: []string{output + "/Mod/main.lua", export + "/Mod/main.lua"},
+		},
+		"junk": {
+			path:         output + "/Mod/.DS_Store",
+			rules:        nil,
+			sources:      nil,
+			destinations: nil,
+		},
+		"sibling prefix": {
+			path:         output + "/Mod2/main.lua",
+			rules:        []string{"expects"},
+			sources:      []string{extract + "/Mod2/main.lua"},
+			destinations: []string{output + "/Mod2/main.lua"},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			copies, err := trace.Explain(test.path)
+			if test.rules == nil {
+				if !errors.Is(err, pd2mm.ErrNotTraced) {
+					t.Fatalf("expected '%s' to not be traced: %v %v", test.path, copies, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var rules, sources, destinations []string
+
+			for _, copy := range copies {
+				rules, sources, destinations = append(rules, copy.Rule), append(sources, copy.Source), append(destinations, copy.Destination)
+			}
+
+			if !slices.Equal(rules, test.rules) || !slices.Equal(sources, test.sources) || !slices.Equal(destinations, test.destinations) {
+				t.Fatalf("unexpected copies: %v %v %v", rules, sources, destinations)
+			}
+		})
+	}
+}
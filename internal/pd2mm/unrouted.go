@@ -0,0 +1,207 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package pd2mm
+
+import (
+	"errors"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/hkmh223/pd2mm/common/filesystem"
+	"github.com/hkmh223/pd2mm/common/logger"
+	"github.com/hkmh223/pd2mm/internal/data"
+)
+
+var ErrNothingDeployed = errors.New("no file of the archive was deployed, check the config and the layout of the archive")
+
+var _routes = newRoutes() //nolint:gochecknoglobals // reason: filled by every copy of a run.
+
+// routes records the sources copied and skipped during a run, so files that no rule routed can be reported.
+type routes struct {
+	mu sync.Mutex
+
+	copied  map[string]struct{}
+	skipped map[string]struct{}
+}
+
+func newRoutes() *routes {
+	return &routes{copied: map[string]struct{}{}, skipped: map[string]struct{}{}} //nolint:exhaustruct // reason: zero mutex.
+}
+
+func (r *routes) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.copied, r.skipped = map[string]struct{}{}, map[string]struct{}{}
+}
+
+// copy records that src was copied by a rule.
+func (r *routes) copy(src string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.copied[absolute(src)] = struct{}{}
+}
+
+// skip records that src was left out on purpose, such as a disabled mod.
+func (r *routes) skip(src string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.skipped[absolute(src)] = struct{}{}
+}
+
+// routed returns whether a rule copied dir, a folder containing it or anything below it.
+func (r *routes) routed(dir string) bool {
+	if copied, _ := r.match(dir); copied {
+		return true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	prefix := absolute(dir) + "/"
+
+	for src := range r.copied {
+		if strings.HasPrefix(src, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// match returns whether file, or a folder containing it, was copied or skipped.
+func (r *routes) match(file string) (bool, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var copied, skipped bool
+
+	for dir := absolute(file); ; {
+		_, isCopied := r.copied[dir]
+		_, isSkipped := r.skipped[dir]
+		copied, skipped = copied || isCopied, skipped || isSkipped
+
+		parent := filepath.ToSlash(filepath.Dir(dir))
+		if parent == dir {
+			return copied, skipped
+		}
+
+		dir = parent
+	}
+}
+
+// reportUnrouted reports the unrouted files of every archive extracted for the config. It runs once all rules of
+// the run copied their files, and each Extract directory is only looked at once, even if several Mods share it.
+func (c Config) reportUnrouted() {
+	visited := map[string]bool{}
+
+	for _, search := range c.Mods {
+		extract := absolute(search.Extract.Path)
+		if visited[extract] {
+			continue
+		}
+
+		visited[extract] = true
+
+		if !filesystem.Exists(extract) {
+			continue
+		}
+
+		directories, err := filesystem.GetTopDirectories(extract)
+		if err != nil {
+			logger.SharedLogger.Warn("failed to get directories", "path", search.Extract.Path, "err", err)
+			continue
+		}
+
+		for _, directory := range directories {
+			PathSearch{PathSearch: &search}.reportUnrouted(directory, filepath.Join(extract, directory))
+		}
+	}
+}
+
+// reportUnrouted adds the files of the extracted archive at path that no rule copied to the SharedReport, under the
+// mod they belong to. A mod that contributed no file at all is a warning, or an error with -strict.
+// Files outside every mod folder are reported under the archive name.
+func (search PathSearch) reportUnrouted(name, path string) {
+	type counts struct {
+		unrouted        []string
+		routed, skipped int
+	}
+
+	dir := absolute(path)
+	mods := map[string]*counts{}
+
+	for _, file := range filesystem.GetFiles(dir) {
+		rel, err := filepath.Rel(dir, file)
+		if err != nil || search.isJunk(rel) {
+			continue
+		}
+
+		id := _mods.id(file, "")
+		if mods[id] == nil {
+			mods[id] = &counts{unrouted: nil, routed: 0, skipped: 0}
+		}
+
+		switch copied, skip := _routes.match(file); {
+		case copied:
+			mods[id].routed++
+		case skip:
+			mods[id].skipped++
+		default:
+			mods[id].unrouted = append(mods[id].unrouted, filesystem.Normalize(rel))
+		}
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(mods)) {
+		entry := mods[id]
+
+		if id == "" {
+			SharedReport.AddUnrouted(name, entry.unrouted)
+			continue
+		}
+
+		SharedReport.AddUnrouted(id, entry.unrouted)
+
+		if entry.routed > 0 || (entry.skipped > 0 && len(entry.unrouted) == 0) {
+			continue
+		}
+
+		if data.Flag.Strict {
+			SharedReport.AddError(id, ErrNothingDeployed)
+		} else {
+			SharedReport.AddWarning(id, ErrNothingDeployed)
+		}
+	}
+}
+
+// absolute returns path relative to the working directory as a normalized absolute path.
+func absolute(path string) string {
+	if !filepath.IsAbs(path) {
+		if cwd, err := filesystem.FromCwd(path); err == nil {
+			path = cwd
+		}
+	}
+
+	return filesystem.Normalize(filepath.Clean(path))
+}
@@ -0,0 +1,109 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package pd2mm
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/hkmh223/pd2mm/common/filesystem"
+	"github.com/hkmh223/pd2mm/common/logger"
+	"github.com/hkmh223/pd2mm/internal/data"
+	"github.com/hkmh223/pd2mm/internal/lang"
+	"github.com/hkmh223/pd2mm/internal/mod"
+)
+
+// ValidateMods checks every mod that landed in the Output directories of the config, each directory once.
+// Script paths from mod.txt and files referenced by main.xml that do not exist are added to the SharedReport.
+func (c Config) ValidateMods() error {
+	visited := map[string]bool{}
+
+	for _, search := range c.Mods {
+		output, err := filesystem.FromCwd(search.Output.Path)
+		if err != nil {
+			return err
+		}
+
+		if visited[output] || !filesystem.Exists(output) {
+			continue
+		}
+
+		visited[output] = true
+
+		directories, err := filesystem.GetTopDirectories(output)
+		if err != nil {
+			return &MError{Header: "ValidateMods", Message: "failed to get directories '" + search.Output.Path + "'", Err: err}
+		}
+
+		for _, directory := range directories {
+			root := filepath.Join(output, directory)
+
+			for _, problem := range mod.Validate(root) {
+				SharedReport.AddError(_mods.output(root, directory), problem)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateCommand handles `validate [file...]`, without files every config in use is validated.
+// Warnings are logged, but only errors fail the command.
+func validateCommand(args []string) error {
+	paths := args
+
+	if len(paths) == 0 {
+		names, err := ConfigNames(Flags{Flags: data.Flag})
+		if err != nil {
+			return err
+		}
+
+		paths = names
+	}
+
+	var problems int
+
+	for _, path := range paths {
+		diagnostics, err := data.Validate(path)
+		if err != nil {
+			return &MError{Header: "validate", Message: "failed to read '" + path + "'", Err: err}
+		}
+
+		for _, diagnostic := range diagnostics {
+			if diagnostic.Warning {
+				logger.SharedLogger.Warn(lang.Lang("configWarningNotify"), "at", diagnostic.Position(), "field", diagnostic.Field, "err", diagnostic.Err)
+				continue
+			}
+
+			logger.SharedLogger.Error(lang.Lang("configProblemNotify"), "at", diagnostic.Position(), "field", diagnostic.Field, "err", diagnostic.Err)
+
+			problems++
+		}
+
+		if len(diagnostics) == 0 {
+			logger.SharedLogger.Info(lang.Lang("configValidNotify"), "path", path)
+		}
+	}
+
+	if problems > 0 {
+		return &MError{Header: "validate", Message: fmt.Sprintf("%d problems in %d configs", problems, len(paths)), Err: data.ErrInvalidConfig}
+	}
+
+	return nil
+}
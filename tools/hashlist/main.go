@@ -0,0 +1,141 @@
+/*
+ * pd2mm
+ * Copyright (C) 2025 pd2mm contributors
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as published
+ * by the Free Software Foundation, either version 3 of the License, or
+ * (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <https://www.gnu.org/licenses/>.
+ */
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/hkmh223/pd2mm/common/crypto"
+)
+
+const usage = `Usage:
+  hashlist hash <string>...
+  hashlist lookup <hashlist> <hash>...
+  hashlist resolve <hashlist> <file>`
+
+func hash(args []string) error {
+	for _, arg := range args {
+		fmt.Printf("%s %s\n", crypto.FormatIDString(crypto.IDString(arg)), arg)
+	}
+
+	return nil
+}
+
+func lookup(hashlist *crypto.Hashlist, args []string) error {
+	for _, arg := range args {
+		hash, err := crypto.ParseIDString(arg)
+		if err != nil {
+			return err
+		}
+
+		str, ok := hashlist.Lookup(hash)
+		if !ok {
+			fmt.Printf("%s ?\n", crypto.FormatIDString(hash))
+			continue
+		}
+
+		fmt.Printf("%s %s\n", crypto.FormatIDString(hash), str)
+	}
+
+	return nil
+}
+
+func resolve(hashlist *crypto.Hashlist, name string) error {
+	file, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var lines []string
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := scanner.Text(); line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	var unresolved int
+
+	for _, line := range lines {
+		hash, err := crypto.ParseIDString(line)
+		if err != nil {
+			return err
+		}
+
+		str, ok := hashlist.Lookup(hash)
+		if !ok {
+			unresolved++
+			str = "?"
+		}
+
+		fmt.Printf("%s %s\n", crypto.FormatIDString(hash), str)
+	}
+
+	fmt.Fprintf(os.Stderr, "resolved %d of %d hashes\n", len(lines)-unresolved, len(lines))
+
+	return nil
+}
+
+func run(args []string) error {
+	if args[0] == "hash" {
+		return hash(args[1:])
+	}
+
+	if len(args) < 3 { //nolint:mnd // allowed
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+
+	hashlist, err := crypto.ReadHashlist(args[1])
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "lookup":
+		return lookup(hashlist, args[2:])
+	case "resolve":
+		return resolve(hashlist, args[2])
+	}
+
+	fmt.Println(usage)
+	os.Exit(1)
+
+	return nil
+}
+
+func main() {
+	if len(os.Args) < 3 { //nolint:mnd // allowed
+		fmt.Println(usage)
+		os.Exit(1)
+	}
+
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+}